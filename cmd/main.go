@@ -1,10 +1,16 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"math/rand"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 	"tower-defense/internal/core"
+	"tower-defense/internal/input"
 	"tower-defense/internal/rendering"
 )
 
@@ -12,57 +18,308 @@ const (
 	gameWidth     = 800
 	gameHeight    = 600
 	frameDuration = time.Second / 60 // 60 FPS
+	maxSpeed      = 8                // fastest replay, in ticks per frame
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
-	gameState := core.NewGameState()
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed for the run")
+	configPath := flag.String("config", "configs/game_config.yaml", "game configuration file")
+	mapPath := flag.String("map", "assets/maps/classic.json", "level to play")
+	wavesPath := flag.String("waves", "", "wave definition file (default: the one named by the map)")
+	recordPath := flag.String("record", "", "record the game to this replay file")
+	savePath := flag.String("save", "savegame.json", "file the game is saved to and loaded from")
+	load := flag.Bool("load", false, "continue the game in the save file")
+	speed := flag.Int("speed", 1, "replay speed in ticks per frame")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags]\n       %s [flags] replay FILE\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "":
+		play(*seed, *configPath, *mapPath, *wavesPath, *recordPath, *savePath, *load)
+	case "replay":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		replay(flag.Arg(1), *speed)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// play runs a game, controlled from the keyboard as described on
+// input.Controller.
+func play(seed int64, configPath, mapPath, wavesPath, recordPath, savePath string, load bool) {
+	wavesPath, err := resolveWaves(mapPath, wavesPath)
+	if err != nil {
+		log.Fatalf("load map: %v", err)
+	}
+	opts := []core.Option{
+		core.WithClock(core.NewClock(frameDuration)),
+		core.WithSeed(seed),
+	}
+	var recorder *core.Recorder
+	if recordPath != "" {
+		hash, err := core.HashFiles(configPath, mapPath, wavesPath)
+		if err != nil {
+			log.Fatalf("record: %v", err)
+		}
+		recorder = core.NewRecorder(core.Replay{
+			Seed:       seed,
+			ConfigHash: hash,
+			Config:     configPath,
+			Map:        mapPath,
+			Waves:      wavesPath,
+			TickDelta:  core.Duration(frameDuration),
+		})
+		opts = append(opts, core.WithRecorder(recorder))
+	}
+	gameState := newGame(configPath, mapPath, wavesPath, opts...)
 	renderer := rendering.NewRenderer()
+	renderer.Watch(gameState.Events())
+
+	if load {
+		if err := gameState.LoadFile(savePath); err != nil {
+			log.Fatalf("load game: %v", err)
+		}
+	} else {
+		// Set up initial game elements
+		setupGame(gameState)
+	}
 
-	// Set up initial game elements
-	setupGame(gameState)
+	keys, restore := openKeyboard()
+	defer restore()
+	controller := input.NewController(gameState.GetGrid())
+	showCursor(renderer, controller, gameState.GetGrid())
+	var results []<-chan core.CommandResult
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Game loop
 	ticker := time.NewTicker(frameDuration)
 	defer ticker.Stop()
 
+loop:
 	for !gameState.IsGameOver() {
 		select {
 		case <-ticker.C:
-			handleInput(gameState)
 			gameState.Update()
+			results = reportFailures(renderer, results)
 			renderer.Render(gameState)
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil // stdin closed; keep playing without controls
+				continue
+			}
+			cmd, action, err := controller.Handle(key, gameState.Snapshot())
+			showCursor(renderer, controller, gameState.GetGrid())
+			if err != nil {
+				renderer.Notify(err.Error())
+			}
+			if cmd != nil {
+				results = append(results, gameState.Submit(cmd))
+			}
+			switch action {
+			case input.ActionSave:
+				if err := gameState.SaveFile(savePath); err != nil {
+					renderer.Notify(fmt.Sprintf("Save failed: %v", err))
+				} else {
+					renderer.Notify("Game saved to " + savePath)
+				}
+			case input.ActionLoad:
+				if err := gameState.LoadFile(savePath); err != nil {
+					renderer.Notify(fmt.Sprintf("Load failed: %v", err))
+				} else {
+					renderer.Notify("Game loaded from " + savePath)
+				}
+			case input.ActionQuit:
+				break loop
+			}
+		case <-interrupt:
+			break loop
 		}
 	}
 
+	restore()
 	fmt.Printf("Game Over! You survived %d waves and earned %d money.\n", gameState.GetWave(), gameState.GetMoney())
+	fmt.Printf("Score: %d\n", gameState.GetScore())
+	fmt.Printf("Seed: %d\n", gameState.GetSeed())
+	if recorder != nil {
+		if err := recorder.Replay().Save(recordPath); err != nil {
+			log.Fatalf("save replay: %v", err)
+		}
+		fmt.Printf("Replay saved to %s\n", recordPath)
+	}
 }
 
-func setupGame(gs *core.GameState) {
-	// Add some initial towers
-	gs.AddTower(core.BasicTower, 210, 300)
-	gs.AddTower(core.SniperTower, 300, 300)
-	// Start the first wave
-	gs.NextWave()
+func showCursor(renderer *rendering.Renderer, controller *input.Controller, grid *core.Grid) {
+	x, y := controller.Cursor(grid)
+	renderer.SetCursor(x, y, controller.Selected)
+}
+
+// reportFailures shows why keyboard commands that have been applied failed,
+// and returns the ones still waiting.
+func reportFailures(renderer *rendering.Renderer, results []<-chan core.CommandResult) []<-chan core.CommandResult {
+	pending := results[:0]
+	for _, result := range results {
+		select {
+		case r := <-result:
+			if r.Err != nil {
+				renderer.Notify(fmt.Sprintf("Cannot %s: %v", r.Command.Kind(), r.Err))
+			}
+		default:
+			pending = append(pending, result)
+		}
+	}
+	return pending
 }
 
-func handleInput(gs *core.GameState) {
-	// This is a placeholder for handling user input
-	// In a real game, you'd handle keyboard/mouse events here
-	// For now, we'll just randomly upgrade or sell a tower occasionally
-	if rand.Float32() < 0.01 { // 1% chance each frame
-		if len(gs.GetTowers()) > 0 {
-			towerIndex := rand.Intn(len(gs.GetTowers()))
-			if rand.Float32() < 0.5 {
-				gs.UpgradeTower(towerIndex)
-			} else {
-				gs.SellTower(towerIndex)
+// replay plays a recorded game back. Press p to pause or resume, s to step
+// one tick, f to speed up, n for normal speed and q to quit.
+func replay(path string, speed int) {
+	rec, err := core.LoadReplay(path)
+	if err != nil {
+		log.Fatalf("load replay: %v", err)
+	}
+	hash, err := core.HashFiles(rec.Config, rec.Map, rec.Waves)
+	if err != nil {
+		log.Fatalf("replay: %v", err)
+	}
+	if hash != rec.ConfigHash {
+		log.Fatalf("replay: %s, %s or %s has changed since the game was recorded", rec.Config, rec.Map, rec.Waves)
+	}
+	gameState := newGame(rec.Config, rec.Map, rec.Waves,
+		core.WithClock(core.NewClock(time.Duration(rec.TickDelta))),
+		core.WithSeed(rec.Seed),
+	)
+	renderer := rendering.NewRenderer()
+	renderer.Watch(gameState.Events())
+	player := core.NewPlayer(rec, gameState)
+
+	keys, restore := openKeyboard()
+	defer restore()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	speed = max(1, min(speed, maxSpeed))
+	paused := false
+	ticker := time.NewTicker(frameDuration)
+	defer ticker.Stop()
+
+	for {
+		steps := 0
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			switch key {
+			case 'p':
+				paused = !paused
+			case 's':
+				steps = 1
+			case 'f':
+				speed = min(speed*2, maxSpeed)
+			case 'n':
+				speed = 1
+			case 'q', input.KeyCtrlC:
+				return
+			}
+		case <-ticker.C:
+			if !paused {
+				steps = speed
 			}
+		case <-interrupt:
+			return
+		}
+		for i := 0; i < steps; i++ {
+			err := player.Step()
+			if errors.Is(err, core.ErrReplayFinished) {
+				restore()
+				fmt.Printf("Replay finished at tick %d: wave %d, score %d.\n", gameState.GetTick(), gameState.GetWave(), gameState.GetScore())
+				return
+			}
+			if err != nil {
+				restore()
+				log.Fatalf("%s: %v", path, err)
+			}
+		}
+		if steps > 0 {
+			renderer.Render(gameState)
 		}
 	}
+}
 
-	// Randomly toggle pause
-	if rand.Float32() < 0.001 { // 0.1% chance each frame
-		gs.TogglePause()
+// openKeyboard reads keys from stdin, switching the terminal to raw mode so
+// that they arrive as they are pressed. Ctrl-C then arrives as a key rather
+// than a signal, so the game loops quit on input.KeyCtrlC as well as on
+// SIGINT. The returned function restores the terminal; it is safe to call
+// more than once. If stdin is not a terminal the keys are still read, but
+// only arrive once Enter is pressed.
+func openKeyboard() (<-chan input.Key, func()) {
+	terminal, err := input.MakeRaw(os.Stdin)
+	if err != nil {
+		log.Printf("keyboard: %v", err)
+		return input.ReadKeys(os.Stdin), func() {}
 	}
+	return input.ReadKeys(os.Stdin), func() { terminal.Restore() }
+}
+
+// resolveWaves picks the wave file for a level: the one given, else the one
+// the map names, else the default.
+func resolveWaves(mapPath, wavesPath string) (string, error) {
+	if wavesPath != "" {
+		return wavesPath, nil
+	}
+	level, err := core.LoadLevel(mapPath)
+	if err != nil {
+		return "", err
+	}
+	if level.WavesFile != "" {
+		return level.WavesFile, nil
+	}
+	return "configs/ennemy_waves.json", nil
+}
+
+func newGame(configPath, mapPath, wavesPath string, opts ...core.Option) *core.GameState {
+	config, err := core.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	towers, err := core.NewTowerRegistry(config.Towers)
+	if err != nil {
+		log.Fatalf("%s: %v", configPath, err)
+	}
+	level, err := core.LoadLevel(mapPath)
+	if err != nil {
+		log.Fatalf("load map: %v", err)
+	}
+	waves, err := core.LoadWaves(wavesPath)
+	if err == nil {
+		err = waves.ValidateSpawns(len(level.Spawns))
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", wavesPath, err)
+	}
+
+	opts = append(opts,
+		core.WithTowerRegistry(towers),
+		core.WithLevel(level),
+		core.WithWaves(waves),
+	)
+	return core.NewGameState(opts...)
+}
+
+func setupGame(gs *core.GameState) {
+	// Add some initial towers
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 225, Y: 275})
+	gs.Submit(core.BuildCommand{Tower: core.SniperTower, X: 300, Y: 200})
+	// Start the first wave
+	gs.Submit(core.CallWaveCommand{})
 }
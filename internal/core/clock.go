@@ -0,0 +1,35 @@
+package core
+
+import "time"
+
+const DefaultTickDelta = time.Second / 60
+
+// Clock is the simulation clock. It only advances when GameState.Update
+// runs, so the same sequence of updates always produces the same game.
+type Clock struct {
+	tick  uint64
+	delta time.Duration
+}
+
+func NewClock(delta time.Duration) *Clock {
+	if delta <= 0 {
+		delta = DefaultTickDelta
+	}
+	return &Clock{delta: delta}
+}
+
+func (c *Clock) Advance() {
+	c.tick++
+}
+
+func (c *Clock) Tick() uint64 {
+	return c.tick
+}
+
+func (c *Clock) Delta() time.Duration {
+	return c.delta
+}
+
+func (c *Clock) Elapsed() time.Duration {
+	return time.Duration(c.tick) * c.delta
+}
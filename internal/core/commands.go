@@ -0,0 +1,167 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+type CommandKind string
+
+const (
+	CommandBuild        CommandKind = "build"
+	CommandUpgrade      CommandKind = "upgrade"
+	CommandSell         CommandKind = "sell"
+	CommandSetTargeting CommandKind = "set_targeting"
+	CommandPause        CommandKind = "pause"
+	CommandCallWave     CommandKind = "call_wave"
+)
+
+// Command is a player action. Commands are queued with Submit and applied at
+// the start of the next Update, in the order they were submitted, so every
+// action lands at a known tick.
+type Command interface {
+	Kind() CommandKind
+	apply(gs *GameState) error
+}
+
+type BuildCommand struct {
+	Tower TowerType `json:"tower"`
+	X     float64   `json:"x"`
+	Y     float64   `json:"y"`
+}
+
+// Commands name towers by ID rather than by position in GetTowers, which
+// changes as towers are sold.
+type UpgradeCommand struct {
+	Tower uint64 `json:"tower"`
+}
+
+type SellCommand struct {
+	Tower uint64 `json:"tower"`
+}
+
+type SetTargetingCommand struct {
+	Tower    uint64 `json:"tower"`
+	Strategy string `json:"strategy"`
+}
+
+// PauseCommand pauses a running game and resumes a paused one.
+type PauseCommand struct{}
+
+// CallWaveCommand starts the next wave without waiting for the current one
+// to be cleared.
+type CallWaveCommand struct{}
+
+func (BuildCommand) Kind() CommandKind        { return CommandBuild }
+func (UpgradeCommand) Kind() CommandKind      { return CommandUpgrade }
+func (SellCommand) Kind() CommandKind         { return CommandSell }
+func (SetTargetingCommand) Kind() CommandKind { return CommandSetTargeting }
+func (PauseCommand) Kind() CommandKind        { return CommandPause }
+func (CallWaveCommand) Kind() CommandKind     { return CommandCallWave }
+
+func (c BuildCommand) apply(gs *GameState) error {
+	return gs.addTower(c.Tower, c.X, c.Y)
+}
+
+func (c UpgradeCommand) apply(gs *GameState) error {
+	index, err := gs.towerIndex(c.Tower)
+	if err != nil {
+		return err
+	}
+	return gs.upgradeTower(index)
+}
+
+func (c SellCommand) apply(gs *GameState) error {
+	index, err := gs.towerIndex(c.Tower)
+	if err != nil {
+		return err
+	}
+	return gs.sellTower(index)
+}
+
+func (c SetTargetingCommand) apply(gs *GameState) error {
+	index, err := gs.towerIndex(c.Tower)
+	if err != nil {
+		return err
+	}
+	return gs.setTowerTargeting(index, c.Strategy)
+}
+
+func (PauseCommand) apply(gs *GameState) error {
+	gs.paused = !gs.paused
+	return nil
+}
+
+func (CallWaveCommand) apply(gs *GameState) error {
+	if gs.lives <= 0 {
+		return ErrGameOver
+	}
+	gs.startNextWave()
+	return nil
+}
+
+var (
+	ErrGameOver   = errors.New("game is over")
+	ErrGameLoaded = errors.New("a saved game was loaded before the command was applied")
+)
+
+// towerIndex finds the tower with the given ID in the tower list.
+func (gs *GameState) towerIndex(id uint64) (int, error) {
+	for i, tower := range gs.world.Towers {
+		if tower.ID == id {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no tower with ID %d", id)
+}
+
+// CommandResult is the outcome of a submitted command.
+type CommandResult struct {
+	Command Command
+	Tick    uint64 // GetTick when the command was applied
+	Err     error
+}
+
+type queuedCommand struct {
+	Command
+	result chan CommandResult
+}
+
+// Submit queues a command for the next Update. The returned channel receives
+// its result once it has been applied; callers that do not care may drop it.
+func (gs *GameState) Submit(cmd Command) <-chan CommandResult {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	result := make(chan CommandResult, 1)
+	gs.commands = append(gs.commands, queuedCommand{Command: cmd, result: result})
+	return result
+}
+
+// PendingCommands is the number of commands waiting for the next Update.
+func (gs *GameState) PendingCommands() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return len(gs.commands)
+}
+
+func (gs *GameState) applyCommands() {
+	commands := gs.commands
+	gs.commands = nil
+	for _, queued := range commands {
+		err := queued.apply(gs)
+		if gs.recorder != nil {
+			gs.recorder.recordCommand(gs.clock.Tick(), queued.Command, err)
+		}
+		queued.result <- CommandResult{Command: queued.Command, Tick: gs.clock.Tick(), Err: err}
+	}
+}
+
+// dropCommands empties the queue without applying anything, failing every
+// command with err.
+func (gs *GameState) dropCommands(err error) {
+	commands := gs.commands
+	gs.commands = nil
+	for _, queued := range commands {
+		queued.result <- CommandResult{Command: queued.Command, Tick: gs.clock.Tick(), Err: err}
+	}
+}
@@ -0,0 +1,301 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"tower-defense/internal/entities"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Towers []TowerDefinition `yaml:"towers"`
+}
+
+type TowerDefinition struct {
+	ID              TowerType           `yaml:"id"`
+	Name            string              `yaml:"name"`
+	Cost            int                 `yaml:"cost"`
+	Range           float64             `yaml:"range"`
+	Damage          int                 `yaml:"damage"`
+	DamageType      entities.DamageType `yaml:"damage_type"`
+	FireRate        time.Duration       `yaml:"fire_rate"`
+	ProjectileSpeed float64             `yaml:"projectile_speed"`
+	Homing          bool                `yaml:"homing"`
+	CritChance      float64             `yaml:"crit_chance"`
+	CritMultiplier  float64             `yaml:"crit_multiplier"`
+	Targeting       string              `yaml:"targeting"`
+	Special         string              `yaml:"special"`
+	Upgrades        []UpgradeDefinition `yaml:"upgrades"`
+	CanHit          []string            `yaml:"can_hit"`
+	Effect          *EffectDefinition   `yaml:"effect"`
+}
+
+type EffectDefinition struct {
+	Kind            entities.EffectKind `yaml:"kind"`
+	Duration        time.Duration       `yaml:"duration"`
+	SpeedMultiplier float64             `yaml:"speed_multiplier"`
+	TickDamage      int                 `yaml:"tick_damage"`
+	TickInterval    time.Duration       `yaml:"tick_interval"`
+	DamageType      entities.DamageType `yaml:"damage_type"`
+	Stacking        entities.StackRule  `yaml:"stacking"`
+	MaxStacks       int                 `yaml:"max_stacks"`
+}
+
+type UpgradeDefinition struct {
+	Cost               int     `yaml:"cost"`
+	Damage             int     `yaml:"damage"`
+	Range              float64 `yaml:"range"`
+	FireRateMultiplier float64 `yaml:"fire_rate_multiplier"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+func ParseConfig(data []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+func (def TowerDefinition) Validate() error {
+	switch {
+	case def.ID == "":
+		return errors.New("missing id")
+	case def.Cost <= 0:
+		return errors.New("cost must be positive")
+	case def.Range <= 0:
+		return errors.New("range must be positive")
+	case def.Damage < 0:
+		return errors.New("damage must not be negative")
+	case def.FireRate <= 0:
+		return errors.New("fire_rate must be positive")
+	case def.ProjectileSpeed < 0:
+		return errors.New("projectile_speed must not be negative")
+	case def.CritChance < 0 || def.CritChance > 1:
+		return errors.New("crit_chance must be between 0 and 1")
+	case def.CritChance > 0 && def.CritMultiplier < 1:
+		return errors.New("crit_multiplier must be at least 1 when crit_chance is set")
+	}
+	if def.DamageType != "" && !def.DamageType.IsValid() {
+		return fmt.Errorf("unknown damage_type %q", def.DamageType)
+	}
+	if def.Targeting != "" {
+		if _, ok := entities.TargetingByName(def.Targeting); !ok {
+			return fmt.Errorf("unknown targeting %q", def.Targeting)
+		}
+	}
+	if def.Special != "" && def.Special != entities.SpecialAOE {
+		return fmt.Errorf("unknown special %q", def.Special)
+	}
+	for _, kind := range def.CanHit {
+		if kind == "" {
+			return errors.New("can_hit entries must not be empty")
+		}
+	}
+	if def.Effect != nil {
+		if err := def.Effect.validate(); err != nil {
+			return fmt.Errorf("effect: %w", err)
+		}
+	}
+	for i, upgrade := range def.Upgrades {
+		if upgrade.Cost < 0 || upgrade.FireRateMultiplier < 0 {
+			return fmt.Errorf("upgrade %d: cost and fire_rate_multiplier must not be negative", i+1)
+		}
+	}
+	return nil
+}
+
+func (def EffectDefinition) validate() error {
+	switch {
+	case !def.Kind.IsValid():
+		return fmt.Errorf("unknown kind %q", def.Kind)
+	case def.Duration <= 0:
+		return errors.New("duration must be positive")
+	case def.SpeedMultiplier < 0:
+		return errors.New("speed_multiplier must not be negative")
+	case def.TickDamage < 0 || def.TickInterval < 0:
+		return errors.New("tick_damage and tick_interval must not be negative")
+	case def.TickDamage > 0 && def.TickInterval == 0:
+		return errors.New("tick_damage needs a tick_interval")
+	case def.DamageType != "" && !def.DamageType.IsValid():
+		return fmt.Errorf("unknown damage_type %q", def.DamageType)
+	case def.Stacking != "" && def.Stacking != entities.StackRefresh && def.Stacking != entities.StackAdd:
+		return fmt.Errorf("unknown stacking %q", def.Stacking)
+	case def.MaxStacks < 0:
+		return errors.New("max_stacks must not be negative")
+	}
+	return nil
+}
+
+func (def TowerDefinition) Build(x, y float64) *entities.Tower {
+	targeting, ok := entities.TargetingByName(def.Targeting)
+	if !ok {
+		targeting = entities.FirstTargeting{}
+	}
+	damageType := def.DamageType
+	if damageType == "" {
+		damageType = entities.DamagePhysical
+	}
+	upgrades := make([]entities.UpgradeStep, len(def.Upgrades))
+	for i, upgrade := range def.Upgrades {
+		upgrades[i] = entities.UpgradeStep{
+			Damage:             upgrade.Damage,
+			Range:              upgrade.Range,
+			FireRateMultiplier: upgrade.FireRateMultiplier,
+			Cost:               upgrade.Cost,
+		}
+	}
+	var effect *entities.StatusEffect
+	if def.Effect != nil {
+		effect = &entities.StatusEffect{
+			Kind:            def.Effect.Kind,
+			Duration:        def.Effect.Duration,
+			SpeedMultiplier: def.Effect.SpeedMultiplier,
+			TickDamage:      def.Effect.TickDamage,
+			TickInterval:    def.Effect.TickInterval,
+			DamageType:      def.Effect.DamageType,
+			Stacking:        def.Effect.Stacking,
+			MaxStacks:       def.Effect.MaxStacks,
+		}
+	}
+	return &entities.Tower{
+		BaseEntity:      entities.BaseEntity{X: x, Y: y},
+		Range:           def.Range,
+		Damage:          def.Damage,
+		DamageType:      damageType,
+		FireRate:        def.FireRate,
+		Level:           1,
+		Cost:            def.Cost,
+		Type:            def.Name,
+		Definition:      string(def.ID),
+		CritChance:      def.CritChance,
+		CritMultiplier:  def.CritMultiplier,
+		ProjectileSpeed: def.ProjectileSpeed,
+		Homing:          def.Homing,
+		Targeting:       targeting,
+		Special:         def.Special,
+		Upgrades:        upgrades,
+		CanHit:          append([]string(nil), def.CanHit...),
+		Effect:          effect,
+	}
+}
+
+// TowerRegistry holds the buildable towers in the order they are offered to
+// the player.
+type TowerRegistry struct {
+	defs  map[TowerType]TowerDefinition
+	order []TowerType
+}
+
+func NewTowerRegistry(defs []TowerDefinition) (*TowerRegistry, error) {
+	if len(defs) == 0 {
+		return nil, errors.New("no towers defined")
+	}
+	registry := &TowerRegistry{defs: make(map[TowerType]TowerDefinition, len(defs))}
+	for i, def := range defs {
+		if err := def.Validate(); err != nil {
+			return nil, fmt.Errorf("tower %d (%s): %w", i+1, def.ID, err)
+		}
+		if _, exists := registry.defs[def.ID]; exists {
+			return nil, fmt.Errorf("tower %d (%s): duplicate id", i+1, def.ID)
+		}
+		if def.Name == "" {
+			def.Name = string(def.ID)
+		}
+		registry.defs[def.ID] = def
+		registry.order = append(registry.order, def.ID)
+	}
+	return registry, nil
+}
+
+// DefaultTowerRegistry describes the built-in towers and is used when no
+// configuration file is supplied.
+func DefaultTowerRegistry() *TowerRegistry {
+	registry, err := NewTowerRegistry([]TowerDefinition{
+		definitionFromTower(BasicTower, entities.NewBasicTower(0, 0)),
+		definitionFromTower(SniperTower, entities.NewSniperTower(0, 0)),
+		definitionFromTower(AOETower, entities.NewAOETower(0, 0)),
+		definitionFromTower(FrostTower, entities.NewFrostTower(0, 0)),
+		definitionFromTower(PoisonTower, entities.NewPoisonTower(0, 0)),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+func definitionFromTower(id TowerType, t *entities.Tower) TowerDefinition {
+	upgrades := make([]UpgradeDefinition, len(t.Upgrades))
+	for i, step := range t.Upgrades {
+		upgrades[i] = UpgradeDefinition{
+			Cost:               step.Cost,
+			Damage:             step.Damage,
+			Range:              step.Range,
+			FireRateMultiplier: step.FireRateMultiplier,
+		}
+	}
+	var effect *EffectDefinition
+	if t.Effect != nil {
+		effect = &EffectDefinition{
+			Kind:            t.Effect.Kind,
+			Duration:        t.Effect.Duration,
+			SpeedMultiplier: t.Effect.SpeedMultiplier,
+			TickDamage:      t.Effect.TickDamage,
+			TickInterval:    t.Effect.TickInterval,
+			DamageType:      t.Effect.DamageType,
+			Stacking:        t.Effect.Stacking,
+			MaxStacks:       t.Effect.MaxStacks,
+		}
+	}
+	return TowerDefinition{
+		ID:              id,
+		Name:            t.Type,
+		Cost:            t.Cost,
+		Range:           t.Range,
+		Damage:          t.Damage,
+		DamageType:      t.DamageType,
+		FireRate:        t.FireRate,
+		ProjectileSpeed: t.ProjectileSpeed,
+		Homing:          t.Homing,
+		CritChance:      t.CritChance,
+		CritMultiplier:  t.CritMultiplier,
+		Targeting:       t.Targeting.Name(),
+		Special:         t.Special,
+		Upgrades:        upgrades,
+		CanHit:          t.CanHit,
+		Effect:          effect,
+	}
+}
+
+func (r *TowerRegistry) Get(id TowerType) (TowerDefinition, bool) {
+	def, ok := r.defs[id]
+	return def, ok
+}
+
+func (r *TowerRegistry) IDs() []TowerType {
+	ids := make([]TowerType, len(r.order))
+	copy(ids, r.order)
+	return ids
+}
+
+func (r *TowerRegistry) Definitions() []TowerDefinition {
+	defs := make([]TowerDefinition, len(r.order))
+	for i, id := range r.order {
+		defs[i] = r.defs[id]
+	}
+	return defs
+}
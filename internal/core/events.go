@@ -0,0 +1,161 @@
+package core
+
+import "sync"
+
+type EventKind string
+
+const (
+	EventEnemyKilled   EventKind = "enemy_killed"
+	EventEnemyLeaked   EventKind = "enemy_leaked"
+	EventTowerBuilt    EventKind = "tower_built"
+	EventTowerSold     EventKind = "tower_sold"
+	EventTowerUpgraded EventKind = "tower_upgraded"
+	EventWaveStarted   EventKind = "wave_started"
+	EventWaveCleared   EventKind = "wave_cleared"
+	EventGameOver      EventKind = "game_over"
+)
+
+// Event is something that happened in the game. Events are plain values
+// copied out of the state, so handlers may keep them.
+type Event interface {
+	Kind() EventKind
+}
+
+type EnemyKilled struct {
+	Tick      uint64
+	EnemyID   uint64
+	Archetype string
+	X, Y      float64
+	Reward    int
+	TowerID   uint64    // tower that got the kill; zero if none
+	TowerType TowerType // its definition; empty if none or not built from one
+}
+
+type EnemyLeaked struct {
+	Tick      uint64
+	EnemyID   uint64
+	Archetype string
+	Damage    int
+	Lives     int // left after the leak
+}
+
+// Tower events name the tower by TowerID, as commands do, and by the
+// definition it was built from. Name is the display name.
+type TowerBuilt struct {
+	Tick      uint64
+	TowerID   uint64
+	TowerType TowerType
+	Name      string
+	X, Y      float64
+	Cost      int
+}
+
+type TowerSold struct {
+	Tick      uint64
+	TowerID   uint64
+	TowerType TowerType
+	Name      string
+	X, Y      float64
+	Level     int
+	Refund    int
+}
+
+type TowerUpgraded struct {
+	Tick      uint64
+	TowerID   uint64
+	TowerType TowerType
+	Name      string
+	X, Y      float64
+	Level     int // after the upgrade
+	Cost      int
+}
+
+type WaveStarted struct {
+	Tick    uint64
+	Wave    int
+	Enemies int // enemies the wave will release
+}
+
+type WaveCleared struct {
+	Tick uint64
+	Wave int
+}
+
+type GameOver struct {
+	Tick  uint64
+	Wave  int
+	Money int
+	Score int
+}
+
+func (EnemyKilled) Kind() EventKind   { return EventEnemyKilled }
+func (EnemyLeaked) Kind() EventKind   { return EventEnemyLeaked }
+func (TowerBuilt) Kind() EventKind    { return EventTowerBuilt }
+func (TowerSold) Kind() EventKind     { return EventTowerSold }
+func (TowerUpgraded) Kind() EventKind { return EventTowerUpgraded }
+func (WaveStarted) Kind() EventKind   { return EventWaveStarted }
+func (WaveCleared) Kind() EventKind   { return EventWaveCleared }
+func (GameOver) Kind() EventKind      { return EventGameOver }
+
+type Subscription uint64
+
+// EventBus delivers events to the handlers subscribed to their kind, in the
+// order they subscribed. It is safe for concurrent use; handlers may
+// subscribe and unsubscribe while an event is being delivered.
+type EventBus struct {
+	mu       sync.Mutex
+	next     Subscription
+	handlers []subscriber
+}
+
+type subscriber struct {
+	id      Subscription
+	kind    EventKind // empty for every kind
+	handler func(Event)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe calls handler for every event of the given kind, or for every
+// event at all if kind is empty.
+func (b *EventBus) Subscribe(kind EventKind, handler func(Event)) Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.next++
+	b.handlers = append(b.handlers, subscriber{id: b.next, kind: kind, handler: handler})
+	return b.next
+}
+
+func (b *EventBus) Unsubscribe(id Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.handlers {
+		if s.id == id {
+			b.handlers = append(b.handlers[:i:i], b.handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *EventBus) Publish(event Event) {
+	b.mu.Lock()
+	handlers := b.handlers
+	b.mu.Unlock()
+	for _, s := range handlers {
+		if s.kind == "" || s.kind == event.Kind() {
+			s.handler(event)
+		}
+	}
+}
+
+// On subscribes a handler for one event type, such as
+//
+//	core.On(bus, func(e core.EnemyKilled) { ... })
+func On[E Event](bus *EventBus, handler func(E)) Subscription {
+	var zero E
+	return bus.Subscribe(zero.Kind(), func(event Event) {
+		handler(event.(E))
+	})
+}
@@ -2,44 +2,155 @@ package core
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
+	"time"
 	"tower-defense/internal/entities"
+	"tower-defense/internal/systems"
+	"tower-defense/internal/utils"
 )
 
-type TowerType int
+// TowerType is the registry ID of a tower definition.
+type TowerType string
 
 const (
-	BasicTower TowerType = iota
-	SniperTower
-	AOETower
+	BasicTower  TowerType = "basic"
+	SniperTower TowerType = "sniper"
+	AOETower    TowerType = "aoe"
+	FrostTower  TowerType = "frost"
+	PoisonTower TowerType = "poison"
 )
 
 type GameState struct {
-	mu         sync.RWMutex
-	towers     []*entities.Tower
-	enemies    []*entities.Enemy
-	lives      int
-	money      int
-	wave       int
-	towerCosts map[TowerType]int
-	paused     bool
-	enemyPath  []entities.BaseEntity
-}
-
-func NewGameState() *GameState {
-	return &GameState{
-		towers:  make([]*entities.Tower, 0, 100), // Pre-allocate space for 100 towers
-		enemies: make([]*entities.Enemy, 0, 200), // Pre-allocate space for 200 enemies
-		lives:   100,
-		money:   1000,
-		wave:    0,
-		towerCosts: map[TowerType]int{
-			BasicTower:  50,
-			SniperTower: 100,
-			AOETower:    150,
+	mu            sync.RWMutex
+	world         systems.World
+	pipeline      *systems.Pipeline
+	nextEnemyID   uint64
+	nextTowerID   uint64
+	lives         int
+	money         int
+	score         int
+	wave          int
+	towerCosts    map[TowerType]int
+	towerRegistry *TowerRegistry
+	waves         *WaveSet
+	spawner       *Spawner
+	grid          *Grid
+	levelName     string
+	decorations   []Decoration
+	paused        bool
+	paths         *PathNetwork
+	maze          *Maze
+	clock         *Clock
+	seed          int64
+	rng           *rand.Rand
+	rngSource     *countingSource
+	commands      []queuedCommand
+	recorder      *Recorder
+	events        *EventBus
+	pending       []Event // published once the lock is released
+}
+
+const DefaultSeed int64 = 1
+
+// enemyCellSize is the cell size of the enemy index, half the reach of a
+// basic tower.
+const enemyCellSize = 50
+
+type Option func(*GameState)
+
+// WithWaves scripts the game's waves. Without it every wave comes from
+// EndlessWave.
+func WithWaves(waves *WaveSet) Option {
+	return func(gs *GameState) {
+		gs.waves = waves
+	}
+}
+
+// WithGrid sets the tile map towers are placed on. Without it the grid is
+// derived from the enemy path.
+func WithGrid(grid *Grid) Option {
+	return func(gs *GameState) {
+		gs.grid = grid
+	}
+}
+
+// WithPaths sets the roads enemies walk. Without it they follow a single
+// built-in path.
+func WithPaths(paths *PathNetwork) Option {
+	return func(gs *GameState) {
+		gs.paths = paths
+	}
+}
+
+// WithLevel plays on a loaded map, taking its grid, path and starting
+// resources. The level's waves are not loaded; pass them with WithWaves.
+func WithLevel(level *Level) Option {
+	return func(gs *GameState) {
+		gs.levelName = level.Name
+		gs.grid = level.Grid
+		gs.paths = level.Paths
+		gs.maze = nil
+		if level.Maze {
+			// Each game gets its own walls. The level was validated on load,
+			// so only a Level built by hand can fail here.
+			maze, err := NewMaze(level.Grid, level.spawnTiles, level.exitTiles)
+			if err != nil {
+				panic(fmt.Sprintf("level %q: maze: %v", level.Name, err))
+			}
+			gs.maze = maze
+		}
+		gs.decorations = level.Decorations
+		gs.money = level.Money
+		gs.lives = level.Lives
+	}
+}
+
+func WithTowerRegistry(registry *TowerRegistry) Option {
+	return func(gs *GameState) {
+		gs.towerRegistry = registry
+	}
+}
+
+// WithSeed seeds the game's random source. Two games built with the same
+// seed and fed the same inputs play out identically.
+func WithSeed(seed int64) Option {
+	return func(gs *GameState) {
+		gs.seed = seed
+	}
+}
+
+// WithEventBus publishes the game's events on bus, so subscribers can be
+// set up before the game starts. Without it the game has a bus of its own.
+func WithEventBus(bus *EventBus) Option {
+	return func(gs *GameState) {
+		gs.events = bus
+	}
+}
+
+func WithClock(clock *Clock) Option {
+	return func(gs *GameState) {
+		gs.clock = clock
+	}
+}
+
+func NewGameState(opts ...Option) *GameState {
+	gs := &GameState{
+		world: systems.World{
+			Towers:      make([]*entities.Tower, 0, 100), // Pre-allocate space for 100 towers
+			Enemies:     make([]*entities.Enemy, 0, 200), // Pre-allocate space for 200 enemies
+			Projectiles: make([]*entities.Projectile, 0, 200),
+			EnemyIndex:  utils.NewSpatialHash[*entities.Enemy](enemyCellSize),
+			EnemyPool:   utils.NewPool[entities.Enemy](),
+			ShotPool:    utils.NewPool[entities.Projectile](),
 		},
+		lives:  100,
+		money:  1000,
+		wave:   0,
 		paused: false,
-		enemyPath: []entities.BaseEntity{
+		paths: SinglePath([]entities.BaseEntity{
 			{X: 0, Y: 300},
 			{X: 200, Y: 300},
 			{X: 200, Y: 100},
@@ -48,14 +159,57 @@ func NewGameState() *GameState {
 			{X: 600, Y: 500},
 			{X: 600, Y: 300},
 			{X: 800, Y: 300},
-		},
+		}),
+		clock:   NewClock(DefaultTickDelta),
+		seed:    DefaultSeed,
+		spawner: NewSpawner(),
+	}
+	gs.pipeline = systems.NewPipeline(
+		spawningSystem{gs},
+		systems.Targeting{},
+		systems.Collision{},
+		systems.Movement{},
+		systems.Death{},
+		systems.Cleanup{},
+		waveSystem{gs},
+	)
+	gs.world.OnLeak = gs.leak
+	gs.world.OnKill = gs.kill
+	for _, opt := range opts {
+		opt(gs)
 	}
+	if gs.towerRegistry == nil {
+		gs.towerRegistry = DefaultTowerRegistry()
+	}
+	if gs.events == nil {
+		gs.events = NewEventBus()
+	}
+	if gs.grid == nil {
+		gs.grid = NewGridForPath(nil)
+		for _, segment := range gs.paths.Segments {
+			gs.grid.MarkPath(segment.Points)
+		}
+	}
+	gs.towerCosts = make(map[TowerType]int)
+	for _, def := range gs.towerRegistry.Definitions() {
+		gs.towerCosts[def.ID] = def.Cost
+	}
+	gs.seedRandom(gs.seed, 0)
+	return gs
 }
 
 func (gs *GameState) AddTower(towerType TowerType, x, y float64) error {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
+	return gs.addTower(towerType, x, y)
+}
 
+func (gs *GameState) addTower(towerType TowerType, x, y float64) error {
+	def, exists := gs.towerRegistry.Get(towerType)
+	if !exists {
+		return errors.New("invalid tower type")
+	}
 	cost, exists := gs.towerCosts[towerType]
 	if !exists {
 		return errors.New("invalid tower type")
@@ -64,61 +218,144 @@ func (gs *GameState) AddTower(towerType TowerType, x, y float64) error {
 		return errors.New("not enough money to add tower")
 	}
 
-	var tower *entities.Tower
-	switch towerType {
-	case BasicTower:
-		tower = entities.NewBasicTower(x, y)
-	case SniperTower:
-		tower = entities.NewSniperTower(x, y)
-	case AOETower:
-		tower = entities.NewAOETower(x, y)
-	default:
-		return errors.New("unknown tower type")
+	col, row := gs.grid.TileAt(x, y)
+	if err := gs.checkPlacement(col, row); err != nil {
+		return err
+	}
+	if gs.maze != nil {
+		if err := gs.checkWall(TilePos{col, row}); err != nil {
+			return err
+		}
 	}
 
-	gs.towers = append(gs.towers, tower)
+	tower := def.Build(gs.grid.TileCenter(col, row))
+	tower.Cost = cost
+	gs.nextTowerID++
+	tower.ID = gs.nextTowerID
+	gs.world.Towers = append(gs.world.Towers, tower)
 	gs.money -= cost
+	if gs.maze != nil {
+		gs.addWall(TilePos{col, row})
+	}
+	gs.emit(TowerBuilt{Tick: gs.clock.Tick(), TowerID: tower.ID, TowerType: towerType, Name: tower.Type, X: tower.X, Y: tower.Y, Cost: cost})
+	return nil
+}
+
+// checkWall rejects maze towers that would stand on an enemy or leave an
+// enemy or spawn with no way out.
+func (gs *GameState) checkWall(tile TilePos) error {
+	occupied := make([]TilePos, 0, len(gs.world.Enemies))
+	for _, enemy := range gs.world.Enemies {
+		col, row := gs.grid.TileAt(enemy.X, enemy.Y)
+		if (TilePos{col, row}) == tile {
+			return &PlacementError{Col: col, Row: row, Err: ErrOccupied}
+		}
+		occupied = append(occupied, TilePos{col, row})
+	}
+	return gs.maze.CheckWall(tile, occupied)
+}
+
+// addWall updates the maze routes for a new tower and reroutes only the
+// enemies whose way ahead ran through its tile.
+func (gs *GameState) addWall(tile TilePos) {
+	if gs.maze.AddWall(tile) {
+		gs.paths = gs.maze.Network()
+	}
+	for _, enemy := range gs.world.Enemies {
+		for _, point := range enemy.Path[enemy.PathIndex:] {
+			col, row := gs.grid.TileAt(point.X, point.Y)
+			if (TilePos{col, row}) == tile {
+				gs.reroute(enemy)
+				break
+			}
+		}
+	}
+}
+
+func (gs *GameState) removeWall(tile TilePos) {
+	gs.maze.RemoveWall(tile)
+	gs.paths = gs.maze.Network()
+	for _, enemy := range gs.world.Enemies {
+		gs.reroute(enemy)
+	}
+}
+
+// reroute sends an enemy from the tile it is on along the shortest way to
+// an exit.
+func (gs *GameState) reroute(enemy *entities.Enemy) {
+	col, row := gs.grid.TileAt(enemy.X, enemy.Y)
+	route := gs.maze.FindPath(TilePos{col, row})
+	if route == nil {
+		return
+	}
+	enemy.Reroute(append([]entities.BaseEntity{enemy.BaseEntity}, gs.maze.Points(route)...))
+}
+
+func (gs *GameState) checkPlacement(col, row int) error {
+	if err := gs.grid.CheckBuildable(col, row); err != nil {
+		return err
+	}
+	for _, tower := range gs.world.Towers {
+		towerCol, towerRow := gs.grid.TileAt(tower.X, tower.Y)
+		if towerCol == col && towerRow == row {
+			return &PlacementError{Col: col, Row: row, Err: ErrOccupied}
+		}
+	}
 	return nil
 }
 
 func (gs *GameState) AddEnemy(enemy *entities.Enemy) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	gs.enemies = append(gs.enemies, enemy)
+	if enemy.ID == 0 {
+		gs.nextEnemyID++
+		enemy.ID = gs.nextEnemyID
+	}
+	gs.world.Enemies = append(gs.world.Enemies, enemy)
 }
 
 func (gs *GameState) RemoveEnemy(index int) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	if index < 0 || index >= len(gs.enemies) {
+	if index < 0 || index >= len(gs.world.Enemies) {
 		return
 	}
-	gs.enemies[index] = gs.enemies[len(gs.enemies)-1]
-	gs.enemies = gs.enemies[:len(gs.enemies)-1]
+	gs.world.RemoveEnemy(index)
 }
 
 func (gs *GameState) DamageEnemy(index int, damage int) bool {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	if index < 0 || index >= len(gs.enemies) {
+	if index < 0 || index >= len(gs.world.Enemies) {
 		return false
 	}
-	enemy := gs.enemies[index]
+	enemy := gs.world.Enemies[index]
 	isDead := enemy.TakeDamage(damage)
 	if isDead {
-		gs.money += enemy.GetReward()
-		gs.enemies[index] = gs.enemies[len(gs.enemies)-1]
-		gs.enemies = gs.enemies[:len(gs.enemies)-1]
+		gs.kill(enemy)
+		gs.world.RemoveEnemy(index)
 	}
 	return isDead
 }
 
 func (gs *GameState) LoseLife(amount int) {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
+	gs.loseLives(amount)
+}
+
+// loseLives takes lives away and announces the end of the game when the
+// last one goes.
+func (gs *GameState) loseLives(amount int) {
+	if gs.lives <= 0 {
+		return
+	}
 	gs.lives -= amount
-	if gs.lives < 0 {
+	if gs.lives <= 0 {
 		gs.lives = 0
+		gs.emit(GameOver{Tick: gs.clock.Tick(), Wave: gs.wave, Money: gs.money, Score: gs.score})
 	}
 }
 
@@ -129,31 +366,70 @@ func (gs *GameState) IsGameOver() bool {
 }
 
 func (gs *GameState) NextWave() {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
+	gs.startNextWave()
+}
+
+// startNextWave queues the next wave and releases any enemies that are due
+// immediately.
+func (gs *GameState) startNextWave() {
 	gs.wave++
-	gs.spawnEnemiesForWave()
+	plan := gs.waves.Plan(gs.wave)
+	enemies := 0
+	for _, group := range plan {
+		enemies += group.Count * max(group.Enemy.SwarmSize, 1)
+	}
+	gs.emit(WaveStarted{Tick: gs.clock.Tick(), Wave: gs.wave, Enemies: enemies})
+	gs.spawner.Load(plan)
+	gs.spawner.Update(0, gs.spawnEnemy)
 }
 
-func (gs *GameState) spawnEnemiesForWave() {
-	numEnemies := gs.wave * 2 // Example: 2 enemies per wave
-	for i := 0; i < numEnemies; i++ {
-		health := 50 + gs.wave*10
-		speed := 1.0 + float64(gs.wave)/10.0
-		reward := 10 + gs.wave
-		damage := 1 + gs.wave/5
-		enemy := entities.NewEnemy(health, reward, damage, speed, gs.enemyPath)
-		gs.enemies = append(gs.enemies, enemy)
+func (gs *GameState) spawnEnemy(group PlannedGroup) {
+	def := group.Enemy
+	// The whole route is chosen up front so progress along it is known from
+	// the start. A swarm sticks together.
+	path := gs.paths.Route(group.Spawn, gs.rng)
+	if len(path) == 0 {
+		return // no way from the spawn to an exit, e.g. a one-point path
+	}
+	for i := 0; i < max(def.SwarmSize, 1); i++ {
+		speed := def.Speed * (0.95 + gs.rng.Float64()*0.1) // +/-5% jitter so a wave spreads out
+		enemy := gs.world.EnemyPool.Get()
+		enemy.Init(def.Health, def.Reward, def.Damage, speed, path)
+		gs.nextEnemyID++
+		enemy.ID = gs.nextEnemyID
+		enemy.Archetype = def.Name
+		enemy.Armor = def.Armor
+		enemy.Resistances = def.Resistances
+		enemy.Immunities = def.Immunities
+		enemy.Flying = def.Flying
+		enemy.Boss = def.Boss
+		gs.world.Enemies = append(gs.world.Enemies, enemy)
 	}
 }
 
+// IsWaveComplete reports whether every enemy of the current wave has been
+// spawned and resolved.
+func (gs *GameState) IsWaveComplete() bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.spawner.Queued() == 0 && len(gs.world.Enemies) == 0
+}
+
 func (gs *GameState) UpgradeTower(index int) error {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	if index < 0 || index >= len(gs.towers) {
+	return gs.upgradeTower(index)
+}
+
+func (gs *GameState) upgradeTower(index int) error {
+	if index < 0 || index >= len(gs.world.Towers) {
 		return errors.New("invalid tower index")
 	}
-	tower := gs.towers[index]
+	tower := gs.world.Towers[index]
 	upgradeCost := tower.GetUpgradeCost()
 	if gs.money < upgradeCost {
 		return errors.New("not enough money to upgrade tower")
@@ -162,20 +438,49 @@ func (gs *GameState) UpgradeTower(index int) error {
 		return err
 	}
 	gs.money -= upgradeCost
+	gs.emit(TowerUpgraded{Tick: gs.clock.Tick(), TowerID: tower.ID, TowerType: TowerType(tower.Definition), Name: tower.Type, X: tower.X, Y: tower.Y, Level: tower.Level, Cost: upgradeCost})
 	return nil
 }
 
 func (gs *GameState) SellTower(index int) error {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	if index < 0 || index >= len(gs.towers) {
+	return gs.sellTower(index)
+}
+
+func (gs *GameState) sellTower(index int) error {
+	if index < 0 || index >= len(gs.world.Towers) {
 		return errors.New("invalid tower index")
 	}
-	tower := gs.towers[index]
+	tower := gs.world.Towers[index]
 	sellValue := tower.GetSellValue()
 	gs.money += sellValue
-	gs.towers[index] = gs.towers[len(gs.towers)-1]
-	gs.towers = gs.towers[:len(gs.towers)-1]
+	gs.world.Towers[index] = gs.world.Towers[len(gs.world.Towers)-1]
+	gs.world.Towers = gs.world.Towers[:len(gs.world.Towers)-1]
+	if gs.maze != nil {
+		col, row := gs.grid.TileAt(tower.X, tower.Y)
+		gs.removeWall(TilePos{col, row})
+	}
+	gs.emit(TowerSold{Tick: gs.clock.Tick(), TowerID: tower.ID, TowerType: TowerType(tower.Definition), Name: tower.Type, X: tower.X, Y: tower.Y, Level: tower.Level, Refund: sellValue})
+	return nil
+}
+
+func (gs *GameState) SetTowerTargeting(index int, strategy string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.setTowerTargeting(index, strategy)
+}
+
+func (gs *GameState) setTowerTargeting(index int, strategy string) error {
+	if index < 0 || index >= len(gs.world.Towers) {
+		return errors.New("invalid tower index")
+	}
+	targeting, ok := entities.TargetingByName(strategy)
+	if !ok {
+		return errors.New("unknown targeting strategy")
+	}
+	gs.world.Towers[index].Targeting = targeting
 	return nil
 }
 
@@ -185,49 +490,152 @@ func (gs *GameState) TogglePause() {
 	gs.paused = !gs.paused
 }
 
+// Update advances the game by one tick, running every enabled system in
+// order. Queued commands are applied first, even while the game is paused.
 func (gs *GameState) Update() {
+	defer gs.publishEvents()
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	if gs.paused {
-		return
+	gs.applyCommands()
+	if !gs.paused {
+		gs.clock.Advance()
+		gs.world.Delta = gs.clock.Delta()
+		gs.pipeline.Update(&gs.world)
 	}
+	if gs.recorder != nil {
+		gs.recorder.recordUpdate(gs.clock.Tick(), gs.stateHash())
+	}
+}
 
-	for _, tower := range gs.towers {
-		tower.Update(gs.enemies)
+func (gs *GameState) leak(enemy *entities.Enemy) {
+	lives := max(gs.lives-enemy.GetDamage(), 0)
+	gs.emit(EnemyLeaked{Tick: gs.clock.Tick(), EnemyID: enemy.ID, Archetype: enemy.Archetype, Damage: enemy.GetDamage(), Lives: lives})
+	gs.loseLives(enemy.GetDamage())
+}
+
+// kill pays out for a dead enemy and credits the tower that killed it.
+// Score counts the health of every enemy killed.
+func (gs *GameState) kill(enemy *entities.Enemy) {
+	gs.money += enemy.GetReward()
+	gs.score += enemy.MaxHealth
+	event := EnemyKilled{Tick: gs.clock.Tick(), EnemyID: enemy.ID, Archetype: enemy.Archetype, X: enemy.X, Y: enemy.Y, Reward: enemy.GetReward()}
+	if tower := enemy.KilledBy; tower != nil {
+		tower.Kills++
+		event.TowerID = tower.ID
+		event.TowerType = TowerType(tower.Definition)
 	}
+	gs.emit(event)
+}
 
-	for i := 0; i < len(gs.enemies); i++ {
-		enemy := gs.enemies[i]
-		if enemy.HasReachedEnd() {
-			gs.lives -= enemy.GetDamage()
-			if gs.lives < 0 {
-				gs.lives = 0
-			}
-			gs.enemies[i] = gs.enemies[len(gs.enemies)-1]
-			gs.enemies = gs.enemies[:len(gs.enemies)-1]
-			i--
-		} else {
-			enemy.Move()
-		}
+// emit queues an event to be published when the current call releases the
+// lock, so handlers are free to call back into the game.
+func (gs *GameState) emit(event Event) {
+	gs.pending = append(gs.pending, event)
+}
+
+// publishEvents delivers the queued events. Methods that emit events defer
+// it before taking the lock, so that it runs after the lock is released.
+func (gs *GameState) publishEvents() {
+	gs.mu.Lock()
+	events := gs.pending
+	gs.pending = nil
+	gs.mu.Unlock()
+	for _, event := range events {
+		gs.events.Publish(event)
 	}
+}
+
+// Events is the bus the game publishes its events on.
+func (gs *GameState) Events() *EventBus {
+	return gs.events
+}
 
-	if len(gs.enemies) == 0 {
-		gs.wave++
-		gs.spawnEnemiesForWave()
+// AddSystem registers a custom system. With before empty it runs after all
+// the others; otherwise it runs just before the system of that name.
+func (gs *GameState) AddSystem(system systems.System, before string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if before == "" {
+		gs.pipeline.Add(system)
+		return nil
 	}
+	return gs.pipeline.InsertBefore(before, system)
+}
+
+// SetSystemEnabled switches a system of the update pipeline on or off.
+func (gs *GameState) SetSystemEnabled(name string, enabled bool) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.pipeline.SetEnabled(name, enabled)
+}
+
+func (gs *GameState) IsSystemEnabled(name string) bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.pipeline.Enabled(name)
 }
 
-// Getter methods for private fields
+// GetSystems lists the systems of the update pipeline in the order they run.
+func (gs *GameState) GetSystems() []string {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.pipeline.Names()
+}
+
+// EnemiesByProgress returns views of the enemies ordered from closest to the
+// exit to furthest from it. Views are copies and may be kept; the enemies
+// themselves are recycled when they are removed, so refer to one by its ID.
+func (gs *GameState) EnemiesByProgress() []EnemyView {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	views := make([]EnemyView, len(gs.world.Enemies))
+	for i, enemy := range gs.world.Enemies {
+		views[i] = newEnemyView(enemy)
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].Remaining < views[j].Remaining
+	})
+	return views
+}
+
+// LeadingEnemy returns a view of the enemy closest to the exit, or nil if
+// there are none.
+func (gs *GameState) LeadingEnemy() *EnemyView {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	var leader *entities.Enemy
+	for _, enemy := range gs.world.Enemies {
+		if leader == nil || enemy.RemainingDistance() < leader.RemainingDistance() {
+			leader = enemy
+		}
+	}
+	if leader == nil {
+		return nil
+	}
+	view := newEnemyView(leader)
+	return &view
+}
+
+// Getter methods for private fields. GetTowers, GetEnemies and
+// GetProjectiles return the live slices, which the next Update modifies.
+// Enemies and projectiles are pooled, so a pointer kept past the next Update
+// may by then be a different object; keep IDs, or use Snapshot, instead.
 func (gs *GameState) GetTowers() []*entities.Tower {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
-	return gs.towers
+	return gs.world.Towers
 }
 
 func (gs *GameState) GetEnemies() []*entities.Enemy {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
-	return gs.enemies
+	return gs.world.Enemies
+}
+
+func (gs *GameState) GetProjectiles() []*entities.Projectile {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.world.Projectiles
 }
 
 func (gs *GameState) GetLives() int {
@@ -242,40 +650,155 @@ func (gs *GameState) GetMoney() int {
 	return gs.money
 }
 
+func (gs *GameState) GetScore() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.score
+}
+
 func (gs *GameState) GetWave() int {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
 	return gs.wave
 }
 
+func (gs *GameState) GetTick() uint64 {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.clock.Tick()
+}
+
+func (gs *GameState) GetElapsed() time.Duration {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.clock.Elapsed()
+}
+
+func (gs *GameState) GetSeed() int64 {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.seed
+}
+
+// seedRandom resets the random source to the given number of draws after
+// seed.
+func (gs *GameState) seedRandom(seed int64, draws uint64) {
+	gs.seed = seed
+	gs.rngSource = newCountingSource(seed, draws)
+	gs.rng = rand.New(gs.rngSource)
+	gs.world.Rng = gs.rng
+}
+
+// RandFloat64 and RandIntn draw from the game's seeded source. Anything
+// outside GameState that needs randomness (such as AI input) must use these
+// so that a run can be replayed from its seed.
+func (gs *GameState) RandFloat64() float64 {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.rng.Float64()
+}
+
+func (gs *GameState) RandIntn(n int) int {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.rng.Intn(n)
+}
+
+func (gs *GameState) GetQueuedEnemies() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.spawner.Queued()
+}
+
+func (gs *GameState) GetGrid() *Grid {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.grid
+}
+
+func (gs *GameState) GetLevelName() string {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.levelName
+}
+
+func (gs *GameState) GetDecorations() []Decoration {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.decorations
+}
+
 func (gs *GameState) GetTowerCosts() map[TowerType]int {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
 	return gs.towerCosts
 }
 
+func (gs *GameState) GetTowerDefinitions() []TowerDefinition {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.towerDefinitions()
+}
+
+func (gs *GameState) towerDefinitions() []TowerDefinition {
+	defs := gs.towerRegistry.Definitions()
+	for i := range defs {
+		if cost, ok := gs.towerCosts[defs[i].ID]; ok {
+			defs[i].Cost = cost
+		}
+	}
+	return defs
+}
+
 func (gs *GameState) IsPaused() bool {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
 	return gs.paused
 }
 
+// GetEnemyPath returns the main route from the first spawn, taking the
+// heaviest branch at every fork.
 func (gs *GameState) GetEnemyPath() []entities.BaseEntity {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
-	return gs.enemyPath
+	return gs.paths.Route(0, nil)
+}
+
+// IsMaze reports whether the level is played in maze mode, where towers
+// block enemies and the paths follow them.
+func (gs *GameState) IsMaze() bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.maze != nil
+}
+
+func (gs *GameState) GetPaths() *PathNetwork {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.paths
 }
 
+// SetTowers replaces the towers, giving an ID to any that lack one. Towers
+// built later get IDs above every ID passed in.
 func (gs *GameState) SetTowers(towers []*entities.Tower) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	gs.towers = towers
+	for _, tower := range towers {
+		gs.nextTowerID = max(gs.nextTowerID, tower.ID)
+	}
+	for _, tower := range towers {
+		if tower.ID == 0 {
+			gs.nextTowerID++
+			tower.ID = gs.nextTowerID
+		}
+	}
+	gs.world.Towers = towers
 }
 
 func (gs *GameState) SetEnemies(enemies []*entities.Enemy) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	gs.enemies = enemies
+	gs.world.Enemies = enemies
 }
 
 func (gs *GameState) SetLives(lives int) {
@@ -311,5 +834,7 @@ func (gs *GameState) SetPaused(paused bool) {
 func (gs *GameState) SetEnemyPath(enemyPath []entities.BaseEntity) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	gs.enemyPath = enemyPath
+	gs.paths = SinglePath(enemyPath)
+	gs.grid.ClearPath()
+	gs.grid.MarkPath(enemyPath)
 }
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"tower-defense/internal/entities"
+)
+
+const (
+	WorldWidth      = 800
+	WorldHeight     = 600
+	DefaultTileSize = 25
+)
+
+type Terrain int
+
+const (
+	TerrainBuildable Terrain = iota
+	TerrainPath
+	TerrainBlocked
+	TerrainWater
+)
+
+func (t Terrain) String() string {
+	switch t {
+	case TerrainBuildable:
+		return "buildable"
+	case TerrainPath:
+		return "path"
+	case TerrainBlocked:
+		return "blocked"
+	case TerrainWater:
+		return "water"
+	default:
+		return fmt.Sprintf("Terrain(%d)", int(t))
+	}
+}
+
+var (
+	ErrOutOfBounds  = errors.New("out of bounds")
+	ErrOnPath       = errors.New("tile is on the enemy path")
+	ErrNotBuildable = errors.New("tile is not buildable")
+	ErrOccupied     = errors.New("tile is already occupied")
+)
+
+// PlacementError reports why a tower could not be built on a tile. Use
+// errors.Is with ErrOutOfBounds, ErrOnPath, ErrNotBuildable or ErrOccupied to
+// tell the reasons apart.
+type PlacementError struct {
+	Col, Row int
+	Err      error
+}
+
+func (e *PlacementError) Error() string {
+	return fmt.Sprintf("cannot build at tile (%d,%d): %v", e.Col, e.Row, e.Err)
+}
+
+func (e *PlacementError) Unwrap() error {
+	return e.Err
+}
+
+// Grid divides the world into square tiles. Tile (0,0) is the top-left one.
+type Grid struct {
+	Cols, Rows int
+	TileSize   float64
+	tiles      []Terrain
+}
+
+func NewGrid(cols, rows int, tileSize float64) *Grid {
+	return &Grid{
+		Cols:     cols,
+		Rows:     rows,
+		TileSize: tileSize,
+		tiles:    make([]Terrain, cols*rows),
+	}
+}
+
+func (g *Grid) Clone() *Grid {
+	clone := *g
+	clone.tiles = append([]Terrain(nil), g.tiles...)
+	return &clone
+}
+
+// NewGridForPath returns a world-sized grid with every tile the path crosses
+// marked as TerrainPath and everything else buildable.
+func NewGridForPath(path []entities.BaseEntity) *Grid {
+	grid := NewGrid(WorldWidth/DefaultTileSize, WorldHeight/DefaultTileSize, DefaultTileSize)
+	grid.MarkPath(path)
+	return grid
+}
+
+// WorldSize is the size of the area covered by the grid in world units.
+func (g *Grid) WorldSize() (float64, float64) {
+	return float64(g.Cols) * g.TileSize, float64(g.Rows) * g.TileSize
+}
+
+func (g *Grid) InBounds(col, row int) bool {
+	return col >= 0 && col < g.Cols && row >= 0 && row < g.Rows
+}
+
+// At returns the terrain of a tile. Tiles outside the grid are blocked.
+func (g *Grid) At(col, row int) Terrain {
+	if !g.InBounds(col, row) {
+		return TerrainBlocked
+	}
+	return g.tiles[row*g.Cols+col]
+}
+
+func (g *Grid) Set(col, row int, terrain Terrain) {
+	if g.InBounds(col, row) {
+		g.tiles[row*g.Cols+col] = terrain
+	}
+}
+
+func (g *Grid) TileAt(x, y float64) (int, int) {
+	return int(math.Floor(x / g.TileSize)), int(math.Floor(y / g.TileSize))
+}
+
+func (g *Grid) TileCenter(col, row int) (float64, float64) {
+	return (float64(col) + 0.5) * g.TileSize, (float64(row) + 0.5) * g.TileSize
+}
+
+// Snap moves a world position to the centre of the tile containing it.
+func (g *Grid) Snap(x, y float64) (float64, float64) {
+	return g.TileCenter(g.TileAt(x, y))
+}
+
+// MarkPath marks every tile the polyline passes through as TerrainPath.
+// Points on the world's edge count towards the nearest tile inside it.
+func (g *Grid) MarkPath(path []entities.BaseEntity) {
+	for i := 0; i+1 < len(path); i++ {
+		start, end := path[i], path[i+1]
+		dx, dy := end.X-start.X, end.Y-start.Y
+		steps := int(math.Ceil(math.Hypot(dx, dy)/(g.TileSize/4))) + 1
+		for s := 0; s <= steps; s++ {
+			f := float64(s) / float64(steps)
+			col, row := g.TileAt(start.X+dx*f, start.Y+dy*f)
+			g.Set(max(0, min(col, g.Cols-1)), max(0, min(row, g.Rows-1)), TerrainPath)
+		}
+	}
+}
+
+// ClearPath turns every path tile back into buildable terrain.
+func (g *Grid) ClearPath() {
+	for i, terrain := range g.tiles {
+		if terrain == TerrainPath {
+			g.tiles[i] = TerrainBuildable
+		}
+	}
+}
+
+// CheckBuildable reports whether the terrain of a tile allows a tower. It
+// does not know about towers already standing there.
+func (g *Grid) CheckBuildable(col, row int) error {
+	if !g.InBounds(col, row) {
+		return &PlacementError{Col: col, Row: row, Err: ErrOutOfBounds}
+	}
+	switch g.At(col, row) {
+	case TerrainBuildable:
+		return nil
+	case TerrainPath:
+		return &PlacementError{Col: col, Row: row, Err: ErrOnPath}
+	default:
+		return &PlacementError{Col: col, Row: row, Err: ErrNotBuildable}
+	}
+}
@@ -0,0 +1,292 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"tower-defense/internal/entities"
+)
+
+// TilePos is a tile coordinate written as [col, row] in map files.
+type TilePos [2]int
+
+type Decoration struct {
+	Tile  TilePos `json:"tile"`
+	Glyph string  `json:"glyph"`
+}
+
+// levelFile is the on-disk map format. Tiles are drawn as strings, one per
+// row, using '.' for buildable ground, '#' for blocked tiles and '~' for
+// water. Path tiles are derived from the path waypoints. A map has either a
+// single path or a list of paths that fork and merge where their ends meet,
+// unless its mode is "maze", in which case it has no paths at all.
+type levelFile struct {
+	Name        string       `json:"name"`
+	Mode        string       `json:"mode"`
+	TileSize    float64      `json:"tile_size"`
+	Tiles       []string     `json:"tiles"`
+	Spawns      []TilePos    `json:"spawns"`
+	Exits       []TilePos    `json:"exits"`
+	Path        []TilePos    `json:"path"`
+	Paths       []pathFile   `json:"paths"`
+	Decorations []Decoration `json:"decorations"`
+	Money       int          `json:"money"`
+	Lives       int          `json:"lives"`
+	Waves       string       `json:"waves"`
+}
+
+type pathFile struct {
+	Waypoints []TilePos `json:"waypoints"`
+	Weight    float64   `json:"weight"`
+}
+
+// Level is a validated map ready to be played. Positions are in world
+// coordinates at tile centres.
+type Level struct {
+	Name   string
+	Grid   *Grid
+	Spawns []entities.BaseEntity
+	Exits  []entities.BaseEntity
+	Paths  *PathNetwork
+	// Maze levels have no fixed roads: enemies find their own way around
+	// the towers. Paths then holds the routes before anything is built.
+	Maze        bool
+	Decorations []Decoration
+	Money       int
+	Lives       int
+	// WavesFile is the wave file named by the map, relative to the map file
+	// for ParseLevel and resolved against its directory by LoadLevel.
+	WavesFile string
+
+	spawnTiles, exitTiles []TilePos
+}
+
+func LoadLevel(path string) (*Level, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	level, err := ParseLevel(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if level.WavesFile != "" && !filepath.IsAbs(level.WavesFile) {
+		level.WavesFile = filepath.Join(filepath.Dir(path), level.WavesFile)
+	}
+	return level, nil
+}
+
+func ParseLevel(data []byte) (*Level, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var file levelFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse map: %w", err)
+	}
+	return file.build()
+}
+
+func (f *levelFile) build() (*Level, error) {
+	switch {
+	case f.TileSize <= 0:
+		return nil, errors.New("tile_size must be positive")
+	case len(f.Tiles) == 0:
+		return nil, errors.New("no tiles")
+	case f.Money < 0:
+		return nil, errors.New("money must not be negative")
+	case f.Lives <= 0:
+		return nil, errors.New("lives must be positive")
+	case len(f.Spawns) == 0:
+		return nil, errors.New("no spawns")
+	case len(f.Exits) == 0:
+		return nil, errors.New("no exits")
+	}
+
+	grid, err := f.buildGrid()
+	if err != nil {
+		return nil, err
+	}
+	level := &Level{
+		Name:        f.Name,
+		Grid:        grid,
+		Decorations: f.Decorations,
+		Money:       f.Money,
+		Lives:       f.Lives,
+		WavesFile:   f.Waves,
+		spawnTiles:  f.Spawns,
+		exitTiles:   f.Exits,
+	}
+
+	for i, tile := range f.Spawns {
+		if !grid.InBounds(tile[0], tile[1]) {
+			return nil, fmt.Errorf("spawn %d: tile %v is out of bounds", i+1, tile)
+		}
+		level.Spawns = append(level.Spawns, tileCenter(grid, tile))
+	}
+	for i, tile := range f.Exits {
+		if !grid.InBounds(tile[0], tile[1]) {
+			return nil, fmt.Errorf("exit %d: tile %v is out of bounds", i+1, tile)
+		}
+		level.Exits = append(level.Exits, tileCenter(grid, tile))
+	}
+	for i, decoration := range f.Decorations {
+		if !grid.InBounds(decoration.Tile[0], decoration.Tile[1]) {
+			return nil, fmt.Errorf("decoration %d: tile %v is out of bounds", i+1, decoration.Tile)
+		}
+		if len([]rune(decoration.Glyph)) != 1 {
+			return nil, fmt.Errorf("decoration %d: glyph must be a single character", i+1)
+		}
+	}
+
+	switch f.Mode {
+	case "", "path":
+	case "maze":
+		if len(f.Path) > 0 || len(f.Paths) > 0 {
+			return nil, errors.New("maze levels must not have a path")
+		}
+		maze, err := NewMaze(grid, f.Spawns, f.Exits)
+		if err != nil {
+			return nil, err
+		}
+		level.Maze = true
+		level.Paths = maze.Network()
+		return level, nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q", f.Mode)
+	}
+
+	level.Paths, err = f.buildPaths(grid)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range level.Paths.Segments {
+		grid.MarkPath(segment.Points)
+	}
+	return level, nil
+}
+
+func (f *levelFile) buildPaths(grid *Grid) (*PathNetwork, error) {
+	paths := f.Paths
+	switch {
+	case len(f.Path) > 0 && len(paths) > 0:
+		return nil, errors.New("use either path or paths, not both")
+	case len(f.Path) > 0:
+		paths = []pathFile{{Waypoints: f.Path}}
+	case len(paths) == 0:
+		return nil, errors.New("no path")
+	}
+
+	spawns := make([]entities.BaseEntity, len(f.Spawns))
+	for i, tile := range f.Spawns {
+		spawns[i] = tileCenter(grid, tile)
+	}
+	segments := make([]PathSegment, len(paths))
+	for i, path := range paths {
+		points, err := buildPath(grid, path.Waypoints)
+		if err != nil {
+			return nil, fmt.Errorf("path %d: %w", i+1, err)
+		}
+		segments[i] = PathSegment{Points: points, Weight: path.Weight}
+	}
+	network, err := NewPathNetwork(spawns, segments)
+	if err != nil {
+		return nil, err
+	}
+	for i, path := range paths {
+		end := path.Waypoints[len(path.Waypoints)-1]
+		if len(network.next(i)) == 0 && !containsTile(f.Exits, end) {
+			return nil, fmt.Errorf("path %d: must end at an exit or where another path starts, ends at %v", i+1, end)
+		}
+	}
+	return network, nil
+}
+
+func (f *levelFile) buildGrid() (*Grid, error) {
+	cols := len([]rune(f.Tiles[0]))
+	grid := NewGrid(cols, len(f.Tiles), f.TileSize)
+	for row, line := range f.Tiles {
+		runes := []rune(line)
+		if len(runes) != cols {
+			return nil, fmt.Errorf("tiles row %d: expected %d columns, got %d", row+1, cols, len(runes))
+		}
+		for col, ch := range runes {
+			switch ch {
+			case '.':
+				grid.Set(col, row, TerrainBuildable)
+			case '#':
+				grid.Set(col, row, TerrainBlocked)
+			case '~':
+				grid.Set(col, row, TerrainWater)
+			default:
+				return nil, fmt.Errorf("tiles row %d, column %d: unknown tile %q", row+1, col+1, ch)
+			}
+		}
+	}
+	return grid, nil
+}
+
+// buildPath checks that the waypoints run along straight lines over
+// walkable ground and converts them to world positions.
+func buildPath(grid *Grid, waypoints []TilePos) ([]entities.BaseEntity, error) {
+	if len(waypoints) < 2 {
+		return nil, errors.New("needs at least two waypoints")
+	}
+	path := make([]entities.BaseEntity, 0, len(waypoints))
+	for i, tile := range waypoints {
+		if !grid.InBounds(tile[0], tile[1]) {
+			return nil, fmt.Errorf("waypoint %d: tile %v is out of bounds", i+1, tile)
+		}
+		if i > 0 {
+			if err := checkWalkable(grid, waypoints[i-1], tile); err != nil {
+				return nil, fmt.Errorf("waypoint %d: %w", i+1, err)
+			}
+		}
+		path = append(path, tileCenter(grid, tile))
+	}
+	return path, nil
+}
+
+func checkWalkable(grid *Grid, from, to TilePos) error {
+	if from[0] != to[0] && from[1] != to[1] {
+		return fmt.Errorf("segment %v to %v is not horizontal or vertical", from, to)
+	}
+	col, row := from[0], from[1]
+	for {
+		if terrain := grid.At(col, row); terrain != TerrainBuildable {
+			return fmt.Errorf("segment %v to %v crosses %s at %v", from, to, terrain, TilePos{col, row})
+		}
+		if col == to[0] && row == to[1] {
+			return nil
+		}
+		col += sign(to[0] - col)
+		row += sign(to[1] - row)
+	}
+}
+
+func containsTile(tiles []TilePos, tile TilePos) bool {
+	for _, t := range tiles {
+		if t == tile {
+			return true
+		}
+	}
+	return false
+}
+
+func tileCenter(grid *Grid, tile TilePos) entities.BaseEntity {
+	x, y := grid.TileCenter(tile[0], tile[1])
+	return entities.BaseEntity{X: x, Y: y}
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
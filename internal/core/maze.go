@@ -0,0 +1,310 @@
+package core
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"tower-defense/internal/entities"
+)
+
+var ErrBlocksPath = errors.New("tower would block every path to the exit")
+
+// Maze is the open-field mode. Enemies walk from tile to tile towards the
+// nearest exit and towers are walls, so the players build the path
+// themselves.
+type Maze struct {
+	grid   *Grid
+	spawns []TilePos
+	exits  []TilePos
+	walls  map[TilePos]bool
+	routes [][]TilePos // one per spawn
+}
+
+func NewMaze(grid *Grid, spawns, exits []TilePos) (*Maze, error) {
+	if len(spawns) == 0 || len(exits) == 0 {
+		return nil, errors.New("maze needs at least one spawn and one exit")
+	}
+	m := &Maze{
+		grid:   grid,
+		spawns: spawns,
+		exits:  exits,
+		walls:  make(map[TilePos]bool),
+		routes: make([][]TilePos, len(spawns)),
+	}
+	for i, spawn := range spawns {
+		if containsTile(exits, spawn) {
+			return nil, fmt.Errorf("spawn %d is also an exit", i+1)
+		}
+		m.routes[i] = m.FindPath(spawn)
+		if m.routes[i] == nil {
+			return nil, fmt.Errorf("spawn %d: no way through to an exit", i+1)
+		}
+	}
+	return m, nil
+}
+
+// Walkable reports whether enemies can cross a tile. Path tiles and open
+// ground are walkable unless a tower stands on them.
+func (m *Maze) Walkable(tile TilePos) bool {
+	switch m.grid.At(tile[0], tile[1]) {
+	case TerrainBuildable, TerrainPath:
+		return !m.walls[tile]
+	}
+	return false
+}
+
+// CheckWall reports ErrBlocksPath if a wall on tile would cut any spawn or
+// any of the given tiles off from every exit.
+func (m *Maze) CheckWall(tile TilePos, occupied []TilePos) error {
+	if m.walls[tile] {
+		return nil
+	}
+	m.walls[tile] = true
+	reachable := m.reachable()
+	delete(m.walls, tile)
+
+	for _, tiles := range [][]TilePos{m.spawns, occupied} {
+		for _, from := range tiles {
+			if !reachable[from] {
+				return &PlacementError{Col: tile[0], Row: tile[1], Err: ErrBlocksPath}
+			}
+		}
+	}
+	return nil
+}
+
+// AddWall blocks a tile and reroutes the spawns whose route crossed it. It
+// reports whether any route changed.
+func (m *Maze) AddWall(tile TilePos) bool {
+	m.walls[tile] = true
+	changed := false
+	for i, route := range m.routes {
+		if containsTile(route, tile) {
+			m.routes[i] = m.FindPath(m.spawns[i])
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RemoveWall opens a tile again. Any route may now be shorter, so all of
+// them are recomputed.
+func (m *Maze) RemoveWall(tile TilePos) {
+	delete(m.walls, tile)
+	for i, spawn := range m.spawns {
+		m.routes[i] = m.FindPath(spawn)
+	}
+}
+
+// restored returns a copy of the maze with the given walls and routes, as
+// when loading a saved game, and the path network for it. Each route must
+// lead from its spawn to an exit one step at a time over tiles that are
+// walkable with those walls.
+func (m *Maze) restored(walls []TilePos, routes [][]TilePos) (*Maze, *PathNetwork, error) {
+	if len(routes) != len(m.spawns) {
+		return nil, nil, fmt.Errorf("%d routes for %d spawns", len(routes), len(m.spawns))
+	}
+	r := *m
+	r.walls = make(map[TilePos]bool, len(walls))
+	for _, wall := range walls {
+		r.walls[wall] = true
+	}
+	for i, route := range routes {
+		switch {
+		case len(route) < 2:
+			return nil, nil, fmt.Errorf("route %d: needs at least two tiles", i+1)
+		case route[0] != m.spawns[i]:
+			return nil, nil, fmt.Errorf("route %d: does not start at its spawn", i+1)
+		case !containsTile(m.exits, route[len(route)-1]):
+			return nil, nil, fmt.Errorf("route %d: does not end at an exit", i+1)
+		}
+		for j, tile := range route {
+			if !r.Walkable(tile) {
+				return nil, nil, fmt.Errorf("route %d: tile (%d,%d) is not walkable", i+1, tile[0], tile[1])
+			}
+			if j > 0 && abs(tile[0]-route[j-1][0])+abs(tile[1]-route[j-1][1]) != 1 {
+				return nil, nil, fmt.Errorf("route %d: jumps from (%d,%d) to (%d,%d)", i+1, route[j-1][0], route[j-1][1], tile[0], tile[1])
+			}
+		}
+	}
+	r.routes = routes
+	network, err := r.network()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &r, network, nil
+}
+
+func (m *Maze) routesCopy() [][]TilePos {
+	routes := make([][]TilePos, len(m.routes))
+	for i, route := range m.routes {
+		routes[i] = append([]TilePos(nil), route...)
+	}
+	return routes
+}
+
+// Network turns the current routes into a path network with one road per
+// spawn.
+func (m *Maze) Network() *PathNetwork {
+	network, err := m.network()
+	if err != nil {
+		panic(err) // routes always start at their spawn and end at an exit
+	}
+	return network
+}
+
+func (m *Maze) network() (*PathNetwork, error) {
+	spawns := make([]entities.BaseEntity, len(m.spawns))
+	segments := make([]PathSegment, len(m.routes))
+	for i, route := range m.routes {
+		spawns[i] = tileCenter(m.grid, m.spawns[i])
+		segments[i] = PathSegment{Points: m.Points(route)}
+	}
+	return NewPathNetwork(spawns, segments)
+}
+
+// Points converts a route to world positions at the tile centres.
+func (m *Maze) Points(route []TilePos) []entities.BaseEntity {
+	points := make([]entities.BaseEntity, len(route))
+	for i, tile := range route {
+		points[i] = tileCenter(m.grid, tile)
+	}
+	return points
+}
+
+// reachable floods out from the exits and returns every tile that can still
+// reach one.
+func (m *Maze) reachable() map[TilePos]bool {
+	seen := make(map[TilePos]bool)
+	queue := make([]TilePos, 0, len(m.exits))
+	for _, exit := range m.exits {
+		if m.Walkable(exit) && !seen[exit] {
+			seen[exit] = true
+			queue = append(queue, exit)
+		}
+	}
+	for len(queue) > 0 {
+		tile := queue[0]
+		queue = queue[1:]
+		for _, next := range neighbours(tile) {
+			if !seen[next] && m.Walkable(next) {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// FindPath runs A* from a tile to the nearest exit, moving in the four grid
+// directions. It returns nil if no exit can be reached.
+func (m *Maze) FindPath(from TilePos) []TilePos {
+	if !m.Walkable(from) {
+		return nil
+	}
+	cost := map[TilePos]int{from: 0}
+	cameFrom := make(map[TilePos]TilePos)
+	open := &tileQueue{}
+	heap.Push(open, &tileNode{tile: from, priority: m.heuristic(from)})
+
+	for open.Len() > 0 {
+		node := heap.Pop(open).(*tileNode)
+		tile := node.tile
+		if node.cost > cost[tile] {
+			continue // superseded by a cheaper route to the same tile
+		}
+		if containsTile(m.exits, tile) {
+			route := []TilePos{tile}
+			for tile != from {
+				tile = cameFrom[tile]
+				route = append(route, tile)
+			}
+			for i, j := 0, len(route)-1; i < j; i, j = i+1, j-1 {
+				route[i], route[j] = route[j], route[i]
+			}
+			return route
+		}
+		for _, next := range neighbours(tile) {
+			if !m.Walkable(next) {
+				continue
+			}
+			nextCost := cost[tile] + 1
+			if known, ok := cost[next]; ok && known <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			cameFrom[next] = tile
+			heap.Push(open, &tileNode{
+				tile:     next,
+				cost:     nextCost,
+				priority: nextCost + m.heuristic(next),
+				seq:      open.pushed,
+			})
+		}
+	}
+	return nil
+}
+
+// heuristic is the Manhattan distance to the nearest exit.
+func (m *Maze) heuristic(tile TilePos) int {
+	best := -1
+	for _, exit := range m.exits {
+		d := abs(exit[0]-tile[0]) + abs(exit[1]-tile[1])
+		if best < 0 || d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+func neighbours(tile TilePos) [4]TilePos {
+	col, row := tile[0], tile[1]
+	return [4]TilePos{{col + 1, row}, {col, row + 1}, {col - 1, row}, {col, row - 1}}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+type tileNode struct {
+	tile     TilePos
+	cost     int
+	priority int
+	seq      int
+}
+
+// tileQueue is a min-heap on priority. Ties go to the tile nearer the exit,
+// then to the one pushed first, so paths are deterministic.
+type tileQueue struct {
+	nodes  []*tileNode
+	pushed int
+}
+
+func (q *tileQueue) Len() int { return len(q.nodes) }
+
+func (q *tileQueue) Less(i, j int) bool {
+	a, b := q.nodes[i], q.nodes[j]
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	if a.cost != b.cost {
+		return a.cost > b.cost
+	}
+	return a.seq < b.seq
+}
+
+func (q *tileQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *tileQueue) Push(x any) {
+	q.nodes = append(q.nodes, x.(*tileNode))
+	q.pushed++
+}
+
+func (q *tileQueue) Pop() any {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}
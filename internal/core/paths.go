@@ -0,0 +1,165 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"tower-defense/internal/entities"
+)
+
+// PathSegment is one stretch of road. Segments connect where one ends and
+// another begins: several segments starting at the same point form a fork,
+// several ending at the same point form a merge.
+type PathSegment struct {
+	Points []entities.BaseEntity
+	Weight float64 // relative chance of taking this branch at a fork, zero means 1
+}
+
+// PathNetwork is the road layout enemies follow from their spawn points to
+// an exit.
+type PathNetwork struct {
+	Spawns   []entities.BaseEntity
+	Segments []PathSegment
+	starts   map[entities.BaseEntity][]int
+}
+
+// NewPathNetwork links the segments and checks that every spawn leads to an
+// exit without loops. A segment that no other segment continues is taken to
+// end at an exit.
+func NewPathNetwork(spawns []entities.BaseEntity, segments []PathSegment) (*PathNetwork, error) {
+	if len(spawns) == 0 {
+		return nil, errors.New("no spawns")
+	}
+	n := &PathNetwork{
+		Spawns:   spawns,
+		Segments: segments,
+		starts:   make(map[entities.BaseEntity][]int),
+	}
+	ends := make(map[entities.BaseEntity]bool)
+	for i, segment := range segments {
+		if len(segment.Points) < 2 {
+			return nil, fmt.Errorf("path %d: needs at least two waypoints", i+1)
+		}
+		if segment.Weight < 0 {
+			return nil, fmt.Errorf("path %d: weight must not be negative", i+1)
+		}
+		n.starts[segment.Points[0]] = append(n.starts[segment.Points[0]], i)
+		ends[segment.Points[len(segment.Points)-1]] = true
+	}
+	isSpawn := make(map[entities.BaseEntity]bool)
+	for i, spawn := range spawns {
+		if len(n.starts[spawn]) == 0 {
+			return nil, fmt.Errorf("spawn %d: no path starts there", i+1)
+		}
+		isSpawn[spawn] = true
+	}
+	for i, segment := range segments {
+		if start := segment.Points[0]; !isSpawn[start] && !ends[start] {
+			return nil, fmt.Errorf("path %d: does not start at a spawn or where another path ends", i+1)
+		}
+	}
+
+	// Depth-first search for a segment that leads back to itself.
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := make([]int, len(segments))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("path %d: leads back to itself", i+1)
+		case done:
+			return nil
+		}
+		state[i] = visiting
+		for _, next := range n.next(i) {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		return nil
+	}
+	for i := range segments {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// SinglePath is a network with one spawn at the start of path.
+func SinglePath(path []entities.BaseEntity) *PathNetwork {
+	n := &PathNetwork{
+		Segments: []PathSegment{{Points: path}},
+		starts:   make(map[entities.BaseEntity][]int),
+	}
+	if len(path) > 0 {
+		n.Spawns = path[:1]
+	}
+	if len(path) > 1 && path[0] != path[len(path)-1] {
+		n.starts[path[0]] = []int{0}
+	}
+	return n
+}
+
+func (n *PathNetwork) next(segment int) []int {
+	points := n.Segments[segment].Points
+	return n.starts[points[len(points)-1]]
+}
+
+// Route picks a way from a spawn to an exit, choosing each fork at random in
+// proportion to the branch weights. With a nil rng it always takes the
+// heaviest branch, which gives the map's main route. Spawns out of range use
+// the first one.
+func (n *PathNetwork) Route(spawn int, rng *rand.Rand) []entities.BaseEntity {
+	if len(n.Spawns) == 0 {
+		return nil
+	}
+	if spawn < 0 || spawn >= len(n.Spawns) {
+		spawn = 0
+	}
+	var route []entities.BaseEntity
+	choices := n.starts[n.Spawns[spawn]]
+	for len(choices) > 0 {
+		segment := n.pick(choices, rng)
+		points := n.Segments[segment].Points
+		if len(route) > 0 {
+			points = points[1:] // shared with the end of the previous segment
+		}
+		route = append(route, points...)
+		choices = n.next(segment)
+	}
+	return route
+}
+
+func (n *PathNetwork) pick(choices []int, rng *rand.Rand) int {
+	best, total := choices[0], 0.0
+	for _, i := range choices {
+		weight := n.weight(i)
+		if weight > n.weight(best) {
+			best = i
+		}
+		total += weight
+	}
+	if rng == nil || len(choices) == 1 {
+		return best
+	}
+	roll := rng.Float64() * total
+	for _, i := range choices {
+		roll -= n.weight(i)
+		if roll < 0 {
+			return i
+		}
+	}
+	return choices[len(choices)-1]
+}
+
+func (n *PathNetwork) weight(segment int) float64 {
+	if w := n.Segments[segment].Weight; w > 0 {
+		return w
+	}
+	return 1
+}
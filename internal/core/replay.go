@@ -0,0 +1,296 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+)
+
+const ReplayVersion = 1
+
+// ReplayCheckpointInterval is how many ticks apart the recorder takes a
+// state hash to check playback against.
+const ReplayCheckpointInterval = 60
+
+// Replay is a recorded game: where it started and every command the player
+// gave. Playing the commands back into a game built from the same files and
+// seed reproduces it exactly; the checkpoints prove that it did.
+type Replay struct {
+	Version    int    `json:"version"`
+	Seed       int64  `json:"seed"`
+	ConfigHash string `json:"config_hash"` // HashFiles of Config, Map and Waves
+	Config     string `json:"config"`
+	Map        string `json:"map"`
+	Waves      string `json:"waves"`
+	// TickDelta is the length of a simulation tick, which must match for
+	// cooldowns and spawns to line up.
+	TickDelta   Duration          `json:"tick_delta"`
+	Ticks       uint64            `json:"ticks"`
+	Commands    []RecordedCommand `json:"commands"`
+	Checkpoints []Checkpoint      `json:"checkpoints"`
+	Final       Checkpoint        `json:"final"`
+}
+
+// RecordedCommand is a command and the tick it was applied at, as reported
+// by GetTick.
+type RecordedCommand struct {
+	Tick    uint64
+	Command Command
+	Failed  bool // the command returned an error when recorded
+}
+
+type Checkpoint struct {
+	Tick uint64 `json:"tick"`
+	Hash uint64 `json:"hash"`
+}
+
+type recordedCommandFile struct {
+	Tick   uint64          `json:"tick"`
+	Kind   CommandKind     `json:"kind"`
+	Args   json.RawMessage `json:"args,omitempty"`
+	Failed bool            `json:"failed,omitempty"`
+}
+
+func (c RecordedCommand) MarshalJSON() ([]byte, error) {
+	args, err := json.Marshal(c.Command)
+	if err != nil {
+		return nil, err
+	}
+	if string(args) == "{}" {
+		args = nil
+	}
+	return json.Marshal(recordedCommandFile{Tick: c.Tick, Kind: c.Command.Kind(), Args: args, Failed: c.Failed})
+}
+
+func (c *RecordedCommand) UnmarshalJSON(data []byte) error {
+	var file recordedCommandFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	cmd, err := decodeCommand(file.Kind, file.Args)
+	if err != nil {
+		return fmt.Errorf("tick %d: %w", file.Tick, err)
+	}
+	*c = RecordedCommand{Tick: file.Tick, Command: cmd, Failed: file.Failed}
+	return nil
+}
+
+func decodeCommand(kind CommandKind, args json.RawMessage) (Command, error) {
+	switch kind {
+	case CommandBuild:
+		return decodeArgs[BuildCommand](kind, args)
+	case CommandUpgrade:
+		return decodeArgs[UpgradeCommand](kind, args)
+	case CommandSell:
+		return decodeArgs[SellCommand](kind, args)
+	case CommandSetTargeting:
+		return decodeArgs[SetTargetingCommand](kind, args)
+	case CommandPause:
+		return PauseCommand{}, nil
+	case CommandCallWave:
+		return CallWaveCommand{}, nil
+	default:
+		return nil, fmt.Errorf("unknown command %q", kind)
+	}
+}
+
+func decodeArgs[C Command](kind CommandKind, args json.RawMessage) (Command, error) {
+	var cmd C
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &cmd); err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+	}
+	return cmd, nil
+}
+
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if replay.Version != ReplayVersion {
+		return nil, fmt.Errorf("%s: unsupported replay version %d", path, replay.Version)
+	}
+	return &replay, nil
+}
+
+func (r *Replay) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// HashFiles fingerprints the files a game was built from, so a replay is
+// never played against different rules than it was recorded with.
+func HashFiles(paths ...string) (string, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		binary.Write(h, binary.LittleEndian, uint64(len(data)))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Recorder captures the commands a game applies, along with regular state
+// hashes. Attach it with WithRecorder.
+type Recorder struct {
+	replay *Replay
+}
+
+// NewRecorder starts a recording. The header describes the game: its seed,
+// files and tick length. Commands and checkpoints are filled in as the game
+// runs.
+func NewRecorder(header Replay) *Recorder {
+	header.Version = ReplayVersion
+	header.Commands = nil
+	header.Checkpoints = nil
+	return &Recorder{replay: &header}
+}
+
+// Replay returns the recording so far. It must not be used while the game
+// is updating.
+func (r *Recorder) Replay() *Replay {
+	return r.replay
+}
+
+func (r *Recorder) recordCommand(tick uint64, cmd Command, err error) {
+	r.replay.Commands = append(r.replay.Commands, RecordedCommand{Tick: tick, Command: cmd, Failed: err != nil})
+}
+
+// recordUpdate notes the state at the end of an Update. While the game is
+// paused the tick stays the same but commands can still change the state,
+// so only the final hash follows them.
+func (r *Recorder) recordUpdate(tick uint64, hash uint64) {
+	r.replay.Ticks = tick
+	r.replay.Final = Checkpoint{Tick: tick, Hash: hash}
+	checkpoints := r.replay.Checkpoints
+	if tick%ReplayCheckpointInterval == 0 && (len(checkpoints) == 0 || checkpoints[len(checkpoints)-1].Tick != tick) {
+		r.replay.Checkpoints = append(checkpoints, r.replay.Final)
+	}
+}
+
+// WithRecorder records every command the game applies.
+func WithRecorder(recorder *Recorder) Option {
+	return func(gs *GameState) {
+		gs.recorder = recorder
+	}
+}
+
+// StateHash fingerprints the simulation state. Two games that have played
+// out identically have the same hash.
+func (gs *GameState) StateHash() uint64 {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.stateHash()
+}
+
+func (gs *GameState) stateHash() uint64 {
+	h := fnv.New64a()
+	write := func(values ...any) {
+		for _, v := range values {
+			binary.Write(h, binary.LittleEndian, v)
+		}
+	}
+	float := math.Float64bits
+	write(gs.clock.Tick(), int64(gs.lives), int64(gs.money), int64(gs.score), int64(gs.wave), int64(gs.spawner.Queued()), gs.paused)
+	for _, tower := range gs.world.Towers {
+		write(float(tower.X), float(tower.Y), int64(tower.Level), int64(tower.Cooldown), int64(tower.Kills))
+	}
+	for _, enemy := range gs.world.Enemies {
+		write(enemy.ID, float(enemy.X), float(enemy.Y), int64(enemy.Health), int64(len(enemy.Effects)))
+	}
+	for _, projectile := range gs.world.Projectiles {
+		write(projectile.TargetID, float(projectile.X), float(projectile.Y))
+	}
+	return h.Sum64()
+}
+
+var ErrReplayFinished = errors.New("replay finished")
+
+// DivergenceError reports that a replayed game no longer matches the
+// recording.
+type DivergenceError struct {
+	Tick   uint64
+	Reason string
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("replay diverged at tick %d: %s", e.Tick, e.Reason)
+}
+
+// Player feeds a replay's commands into a game built the same way as the
+// recorded one and checks that it plays out the same.
+type Player struct {
+	replay     *Replay
+	gs         *GameState
+	next       int
+	checkpoint int
+}
+
+func NewPlayer(replay *Replay, gs *GameState) *Player {
+	return &Player{replay: replay, gs: gs}
+}
+
+// Done reports whether the whole recording has been played.
+func (p *Player) Done() bool {
+	return p.next >= len(p.replay.Commands) && p.gs.GetTick() >= p.replay.Ticks
+}
+
+// Step submits the commands due at the current tick, updates the game once
+// and checks the result against the recording. It returns ErrReplayFinished
+// once there is nothing left to play and a *DivergenceError if the game no
+// longer matches.
+func (p *Player) Step() error {
+	if p.Done() {
+		return ErrReplayFinished
+	}
+	tick := p.gs.GetTick()
+	var due []RecordedCommand
+	var results []<-chan CommandResult
+	for p.next < len(p.replay.Commands) && p.replay.Commands[p.next].Tick <= tick {
+		recorded := p.replay.Commands[p.next]
+		if recorded.Tick < tick {
+			return &DivergenceError{Tick: tick, Reason: fmt.Sprintf("missed %s command for tick %d", recorded.Command.Kind(), recorded.Tick)}
+		}
+		due = append(due, recorded)
+		results = append(results, p.gs.Submit(recorded.Command))
+		p.next++
+	}
+	p.gs.Update()
+
+	for i, result := range results {
+		if failed := (<-result).Err != nil; failed != due[i].Failed {
+			return &DivergenceError{Tick: tick, Reason: fmt.Sprintf("%s command failed=%t, recorded failed=%t", due[i].Command.Kind(), failed, due[i].Failed)}
+		}
+	}
+	tick = p.gs.GetTick()
+	hash := p.gs.StateHash()
+	for p.checkpoint < len(p.replay.Checkpoints) && p.replay.Checkpoints[p.checkpoint].Tick <= tick {
+		checkpoint := p.replay.Checkpoints[p.checkpoint]
+		p.checkpoint++
+		if checkpoint.Tick == tick && checkpoint.Hash != hash {
+			return &DivergenceError{Tick: tick, Reason: "state does not match checkpoint"}
+		}
+	}
+	if p.Done() && p.replay.Final.Tick == tick && p.replay.Final.Hash != hash {
+		return &DivergenceError{Tick: tick, Reason: "final state does not match"}
+	}
+	return nil
+}
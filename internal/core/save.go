@@ -0,0 +1,640 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"tower-defense/internal/entities"
+)
+
+// SaveVersion is the version of the save format written by Save.
+const SaveVersion = 2
+
+// saveMigrations[v] upgrades a save from version v to v+1. They work on the
+// raw JSON object so that fields can be renamed, split or filled in before
+// the save is decoded. Add one here whenever the format changes.
+var saveMigrations = map[int]func(save map[string]json.RawMessage) error{
+	1: addTowerIDs,
+}
+
+// addTowerIDs numbers the towers of a version 1 save, which had no IDs, in
+// the order they are listed.
+func addTowerIDs(save map[string]json.RawMessage) error {
+	var towers []map[string]json.RawMessage
+	if raw, ok := save["towers"]; ok {
+		if err := json.Unmarshal(raw, &towers); err != nil {
+			return fmt.Errorf("towers: %w", err)
+		}
+	}
+	for i, tower := range towers {
+		tower["id"], _ = json.Marshal(i + 1)
+	}
+	var err error
+	if save["towers"], err = json.Marshal(towers); err != nil {
+		return err
+	}
+	save["next_tower_id"], err = json.Marshal(len(towers))
+	return err
+}
+
+var ErrNewerSave = errors.New("save was written by a newer version of the game")
+
+// saveFile is the on-disk format of a game in progress. Only the state that
+// changes during play is stored; the level, tower catalogue and waves come
+// from the game it is loaded into, which must be built the same way as the
+// one that was saved.
+type saveFile struct {
+	Version     int               `json:"version"`
+	Level       string            `json:"level"`
+	Seed        int64             `json:"seed"`
+	RandDraws   uint64            `json:"rand_draws"`
+	Tick        uint64            `json:"tick"`
+	Lives       int               `json:"lives"`
+	Money       int               `json:"money"`
+	Score       int               `json:"score"`
+	Wave        int               `json:"wave"`
+	Paused      bool              `json:"paused"`
+	NextEnemyID uint64            `json:"next_enemy_id"`
+	NextTowerID uint64            `json:"next_tower_id"`
+	TowerCosts  map[TowerType]int `json:"tower_costs"`
+	Towers      []savedTower      `json:"towers"`
+	Enemies     []savedEnemy      `json:"enemies"`
+	Projectiles []savedProjectile `json:"projectiles"`
+	Spawns      []savedSpawn      `json:"spawns"`
+	MazeRoutes  [][]TilePos       `json:"maze_routes,omitempty"`
+}
+
+type savedPoint [2]float64
+
+type savedTower struct {
+	ID              uint64              `json:"id"`
+	Type            string              `json:"type"`
+	Definition      TowerType           `json:"definition,omitempty"`
+	Pos             savedPoint          `json:"pos"`
+	Range           float64             `json:"range"`
+	Damage          int                 `json:"damage"`
+	DamageType      entities.DamageType `json:"damage_type"`
+	FireRate        Duration            `json:"fire_rate"`
+	Cooldown        Duration            `json:"cooldown"`
+	Level           int                 `json:"level"`
+	Cost            int                 `json:"cost"`
+	CritChance      float64             `json:"crit_chance"`
+	CritMultiplier  float64             `json:"crit_multiplier"`
+	ProjectileSpeed float64             `json:"projectile_speed"`
+	Homing          bool                `json:"homing"`
+	Targeting       string              `json:"targeting"`
+	Special         string              `json:"special,omitempty"`
+	Upgrades        []savedUpgrade      `json:"upgrades"`
+	Effect          *savedEffect        `json:"effect,omitempty"`
+	CanHit          []string            `json:"can_hit,omitempty"`
+	Kills           int                 `json:"kills"`
+}
+
+type savedUpgrade struct {
+	Damage             int     `json:"damage"`
+	Range              float64 `json:"range"`
+	FireRateMultiplier float64 `json:"fire_rate_multiplier"`
+	Cost               int     `json:"cost"`
+}
+
+type savedEffect struct {
+	Kind            entities.EffectKind `json:"kind"`
+	Duration        Duration            `json:"duration"`
+	SpeedMultiplier float64             `json:"speed_multiplier,omitempty"`
+	TickDamage      int                 `json:"tick_damage,omitempty"`
+	TickInterval    Duration            `json:"tick_interval,omitempty"`
+	DamageType      entities.DamageType `json:"damage_type,omitempty"`
+	Stacking        entities.StackRule  `json:"stacking,omitempty"`
+	MaxStacks       int                 `json:"max_stacks,omitempty"`
+	SinceTick       Duration            `json:"since_tick,omitempty"`
+	// Source is an index into towers. An effect from a tower that has since
+	// been sold carries a copy of it in SoldSource instead, as projectiles do.
+	Source     *int        `json:"source,omitempty"`
+	SoldSource *savedTower `json:"sold_source,omitempty"`
+}
+
+type savedEnemy struct {
+	ID          uint64                `json:"id"`
+	Archetype   string                `json:"archetype,omitempty"`
+	Pos         savedPoint            `json:"pos"`
+	Health      int                   `json:"health"`
+	MaxHealth   int                   `json:"max_health"`
+	Speed       float64               `json:"speed"`
+	Reward      int                   `json:"reward"`
+	Damage      int                   `json:"damage"`
+	Path        []savedPoint          `json:"path"`
+	PathIndex   int                   `json:"path_index"`
+	Distance    float64               `json:"distance"`
+	PathLength  float64               `json:"path_length"`
+	Armor       int                   `json:"armor,omitempty"`
+	Resistances entities.Resistances  `json:"resistances,omitempty"`
+	Flying      bool                  `json:"flying,omitempty"`
+	Boss        bool                  `json:"boss,omitempty"`
+	Effects     []savedEffect         `json:"effects,omitempty"`
+	Immunities  []entities.EffectKind `json:"immunities,omitempty"`
+}
+
+type savedProjectile struct {
+	Pos      savedPoint `json:"pos"`
+	Speed    float64    `json:"speed"`
+	Damage   int        `json:"damage"`
+	Homing   bool       `json:"homing"`
+	TargetID uint64     `json:"target_id"`
+	Aim      savedPoint `json:"aim"`
+	// Source is an index into towers. A shot from a tower that has since
+	// been sold carries a copy of it in SoldSource instead.
+	Source     int         `json:"source"`
+	SoldSource *savedTower `json:"sold_source,omitempty"`
+}
+
+type savedSpawn struct {
+	Enemy     string          `json:"enemy"`
+	Stats     EnemyDefinition `json:"stats"`
+	Count     int             `json:"count"`
+	Interval  Duration        `json:"interval"`
+	Delay     Duration        `json:"delay"`
+	Spawn     int             `json:"spawn"`
+	Remaining int             `json:"remaining"`
+	Wait      Duration        `json:"wait"`
+}
+
+// Save writes the game in progress to w. Commands that have been submitted
+// but not yet applied are not saved.
+func (gs *GameState) Save(w io.Writer) error {
+	gs.mu.RLock()
+	save := gs.saveFile()
+	gs.mu.RUnlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(save)
+}
+
+// Load replaces the game in progress with one read from r. The game must
+// have been built with the same level, towers and waves as the saved one.
+// On error the game is left as it was.
+func (gs *GameState) Load(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	save, err := decodeSave(data)
+	if err != nil {
+		return err
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if err := gs.restore(save); err != nil {
+		return err
+	}
+	// Nothing queued against the old game may touch the loaded one.
+	gs.dropCommands(ErrGameLoaded)
+	gs.pending = nil
+	return nil
+}
+
+func (gs *GameState) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gs.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (gs *GameState) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := gs.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// decodeSave reads a save of any supported version, migrating it to the
+// current one.
+func decodeSave(data []byte) (*saveFile, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse save: %w", err)
+	}
+	var version int
+	if err := json.Unmarshal(raw["version"], &version); err != nil {
+		return nil, fmt.Errorf("parse save: version: %w", err)
+	}
+	if version > SaveVersion {
+		return nil, fmt.Errorf("version %d: %w", version, ErrNewerSave)
+	}
+	for ; version < SaveVersion; version++ {
+		migrate, ok := saveMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("unsupported save version %d", version)
+		}
+		if err := migrate(raw); err != nil {
+			return nil, fmt.Errorf("migrate save from version %d: %w", version, err)
+		}
+	}
+	raw["version"], _ = json.Marshal(SaveVersion)
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, fmt.Errorf("parse save: %w", err)
+	}
+	return &save, nil
+}
+
+func (gs *GameState) saveFile() *saveFile {
+	save := &saveFile{
+		Version:     SaveVersion,
+		Level:       gs.levelName,
+		Seed:        gs.seed,
+		RandDraws:   gs.rngSource.draws,
+		Tick:        gs.clock.Tick(),
+		Lives:       gs.lives,
+		Money:       gs.money,
+		Score:       gs.score,
+		Wave:        gs.wave,
+		Paused:      gs.paused,
+		NextEnemyID: gs.nextEnemyID,
+		NextTowerID: gs.nextTowerID,
+		TowerCosts:  gs.towerCosts,
+	}
+	towerIndex := make(map[*entities.Tower]int, len(gs.world.Towers))
+	for i, tower := range gs.world.Towers {
+		towerIndex[tower] = i
+		save.Towers = append(save.Towers, saveTower(tower))
+	}
+	for _, enemy := range gs.world.Enemies {
+		saved := savedEnemy{
+			ID:          enemy.ID,
+			Archetype:   enemy.Archetype,
+			Pos:         savedPoint{enemy.X, enemy.Y},
+			Health:      enemy.Health,
+			MaxHealth:   enemy.MaxHealth,
+			Speed:       enemy.Speed,
+			Reward:      enemy.Reward,
+			Damage:      enemy.Damage,
+			PathIndex:   enemy.PathIndex,
+			Distance:    enemy.Distance,
+			PathLength:  enemy.PathLength(),
+			Armor:       enemy.Armor,
+			Resistances: enemy.Resistances,
+			Flying:      enemy.Flying,
+			Boss:        enemy.Boss,
+			Immunities:  enemy.Immunities,
+		}
+		for _, point := range enemy.Path {
+			saved.Path = append(saved.Path, savedPoint{point.X, point.Y})
+		}
+		for _, effect := range enemy.Effects {
+			savedEffect := saveEffect(effect)
+			if i, ok := towerIndex[effect.Source]; ok {
+				savedEffect.Source = &i
+			} else if effect.Source != nil {
+				sold := saveTower(effect.Source)
+				savedEffect.SoldSource = &sold
+			}
+			saved.Effects = append(saved.Effects, savedEffect)
+		}
+		save.Enemies = append(save.Enemies, saved)
+	}
+	for _, projectile := range gs.world.Projectiles {
+		saved := savedProjectile{
+			Pos:      savedPoint{projectile.X, projectile.Y},
+			Speed:    projectile.Speed,
+			Damage:   projectile.Damage,
+			Homing:   projectile.Homing,
+			TargetID: projectile.TargetID,
+			Aim:      savedPoint{projectile.AimX, projectile.AimY},
+			Source:   -1,
+		}
+		if i, ok := towerIndex[projectile.Source]; ok {
+			saved.Source = i
+		} else {
+			sold := saveTower(projectile.Source)
+			saved.SoldSource = &sold
+		}
+		save.Projectiles = append(save.Projectiles, saved)
+	}
+	for _, group := range gs.spawner.groups {
+		save.Spawns = append(save.Spawns, savedSpawn{
+			Enemy:     group.Enemy.Name,
+			Stats:     group.Enemy,
+			Count:     group.Count,
+			Interval:  Duration(group.Interval),
+			Delay:     Duration(group.Delay),
+			Spawn:     group.Spawn,
+			Remaining: group.remaining,
+			Wait:      Duration(group.wait),
+		})
+	}
+	if gs.maze != nil {
+		save.MazeRoutes = gs.maze.routesCopy()
+	}
+	return save
+}
+
+func saveTower(tower *entities.Tower) savedTower {
+	targeting := "first"
+	if tower.Targeting != nil {
+		targeting = tower.Targeting.Name()
+	}
+	saved := savedTower{
+		ID:              tower.ID,
+		Type:            tower.Type,
+		Definition:      TowerType(tower.Definition),
+		Pos:             savedPoint{tower.X, tower.Y},
+		Range:           tower.Range,
+		Damage:          tower.Damage,
+		DamageType:      tower.DamageType,
+		FireRate:        Duration(tower.FireRate),
+		Cooldown:        Duration(tower.Cooldown),
+		Level:           tower.Level,
+		Cost:            tower.Cost,
+		CritChance:      tower.CritChance,
+		CritMultiplier:  tower.CritMultiplier,
+		ProjectileSpeed: tower.ProjectileSpeed,
+		Homing:          tower.Homing,
+		Targeting:       targeting,
+		Special:         tower.Special,
+		CanHit:          tower.CanHit,
+		Kills:           tower.Kills,
+	}
+	for _, step := range tower.Upgrades {
+		saved.Upgrades = append(saved.Upgrades, savedUpgrade(step))
+	}
+	if tower.Effect != nil {
+		effect := saveEffect(*tower.Effect)
+		saved.Effect = &effect
+	}
+	return saved
+}
+
+func saveEffect(effect entities.StatusEffect) savedEffect {
+	return savedEffect{
+		Kind:            effect.Kind,
+		Duration:        Duration(effect.Duration),
+		SpeedMultiplier: effect.SpeedMultiplier,
+		TickDamage:      effect.TickDamage,
+		TickInterval:    Duration(effect.TickInterval),
+		DamageType:      effect.DamageType,
+		Stacking:        effect.Stacking,
+		MaxStacks:       effect.MaxStacks,
+		SinceTick:       Duration(effect.SinceTick),
+	}
+}
+
+// restore rebuilds the state from a save. Everything is checked and built
+// before the game is touched.
+func (gs *GameState) restore(save *saveFile) error {
+	if gs.recorder != nil {
+		return errors.New("cannot load a save into a game that is being recorded")
+	}
+	if save.Level != gs.levelName {
+		return fmt.Errorf("save is for level %q, not %q", save.Level, gs.levelName)
+	}
+	towers := make([]*entities.Tower, len(save.Towers))
+	towerIDs := make(map[uint64]bool, len(save.Towers))
+	for i, saved := range save.Towers {
+		if saved.ID == 0 || saved.ID > save.NextTowerID || towerIDs[saved.ID] {
+			return fmt.Errorf("tower %d: bad ID %d", i+1, saved.ID)
+		}
+		towerIDs[saved.ID] = true
+		tower, err := loadTower(saved)
+		if err != nil {
+			return fmt.Errorf("tower %d: %w", i+1, err)
+		}
+		towers[i] = tower
+	}
+	loadEffect := func(saved savedEffect) (entities.StatusEffect, error) {
+		effect := entities.StatusEffect{
+			Kind:            saved.Kind,
+			Duration:        time.Duration(saved.Duration),
+			SpeedMultiplier: saved.SpeedMultiplier,
+			TickDamage:      saved.TickDamage,
+			TickInterval:    time.Duration(saved.TickInterval),
+			DamageType:      saved.DamageType,
+			Stacking:        saved.Stacking,
+			MaxStacks:       saved.MaxStacks,
+			SinceTick:       time.Duration(saved.SinceTick),
+		}
+		switch {
+		case saved.SoldSource != nil:
+			source, err := loadTower(*saved.SoldSource)
+			if err != nil {
+				return effect, fmt.Errorf("effect source: %w", err)
+			}
+			effect.Source = source
+		case saved.Source != nil:
+			if *saved.Source < 0 || *saved.Source >= len(towers) {
+				return effect, fmt.Errorf("effect source %d out of range", *saved.Source)
+			}
+			effect.Source = towers[*saved.Source]
+		}
+		return effect, nil
+	}
+
+	enemies := make([]entities.Enemy, len(save.Enemies))
+	byID := make(map[uint64]int, len(save.Enemies))
+	for i, saved := range save.Enemies {
+		if len(saved.Path) == 0 {
+			return fmt.Errorf("enemy %d: no path", i+1)
+		}
+		if saved.PathIndex < 0 || saved.PathIndex >= len(saved.Path) {
+			return fmt.Errorf("enemy %d: path index %d out of range", i+1, saved.PathIndex)
+		}
+		enemy := entities.Enemy{
+			BaseEntity:  entities.BaseEntity{X: saved.Pos[0], Y: saved.Pos[1]},
+			ID:          saved.ID,
+			Health:      saved.Health,
+			MaxHealth:   saved.MaxHealth,
+			Speed:       saved.Speed,
+			Reward:      saved.Reward,
+			Damage:      saved.Damage,
+			PathIndex:   saved.PathIndex,
+			Distance:    saved.Distance,
+			Archetype:   saved.Archetype,
+			Armor:       saved.Armor,
+			Resistances: saved.Resistances,
+			Flying:      saved.Flying,
+			Boss:        saved.Boss,
+			Immunities:  saved.Immunities,
+		}
+		for _, point := range saved.Path {
+			enemy.Path = append(enemy.Path, entities.BaseEntity{X: point[0], Y: point[1]})
+		}
+		enemy.SetPathLength(saved.PathLength)
+		for _, savedEffect := range saved.Effects {
+			effect, err := loadEffect(savedEffect)
+			if err != nil {
+				return fmt.Errorf("enemy %d: %w", i+1, err)
+			}
+			enemy.Effects = append(enemy.Effects, effect)
+		}
+		enemies[i] = enemy
+		byID[saved.ID] = i
+	}
+
+	var soldSources []*entities.Tower
+	for i, saved := range save.Projectiles {
+		if saved.SoldSource != nil {
+			tower, err := loadTower(*saved.SoldSource)
+			if err != nil {
+				return fmt.Errorf("projectile %d: %w", i+1, err)
+			}
+			soldSources = append(soldSources, tower)
+		} else if saved.Source < 0 || saved.Source >= len(towers) {
+			return fmt.Errorf("projectile %d: source %d out of range", i+1, saved.Source)
+		}
+	}
+
+	var maze *Maze
+	var paths *PathNetwork
+	if gs.maze != nil {
+		walls := make([]TilePos, len(towers))
+		for i, tower := range towers {
+			col, row := gs.grid.TileAt(tower.X, tower.Y)
+			walls[i] = TilePos{col, row}
+		}
+		var err error
+		if maze, paths, err = gs.maze.restored(walls, save.MazeRoutes); err != nil {
+			return fmt.Errorf("maze: %w", err)
+		}
+	}
+
+	// Nothing can fail from here on.
+	for _, enemy := range gs.world.Enemies {
+		gs.world.EnemyPool.Put(enemy)
+	}
+	for _, projectile := range gs.world.Projectiles {
+		gs.world.ShotPool.Put(projectile)
+	}
+	gs.world.Towers = towers
+	gs.world.Enemies = gs.world.Enemies[:0]
+	for i := range enemies {
+		enemy := gs.world.EnemyPool.Get()
+		*enemy = enemies[i]
+		gs.world.Enemies = append(gs.world.Enemies, enemy)
+	}
+	gs.world.Projectiles = gs.world.Projectiles[:0]
+	for _, saved := range save.Projectiles {
+		projectile := gs.world.ShotPool.Get()
+		source := (*entities.Tower)(nil)
+		if saved.SoldSource != nil {
+			source, soldSources = soldSources[0], soldSources[1:]
+		} else {
+			source = towers[saved.Source]
+		}
+		// A shot whose target is gone gets a stand-in that reads as dead.
+		target := &entities.Enemy{}
+		if i, ok := byID[saved.TargetID]; ok {
+			target = gs.world.Enemies[i]
+		}
+		*projectile = entities.Projectile{
+			BaseEntity: entities.BaseEntity{X: saved.Pos[0], Y: saved.Pos[1]},
+			Speed:      saved.Speed,
+			Damage:     saved.Damage,
+			Homing:     saved.Homing,
+			Target:     target,
+			TargetID:   saved.TargetID,
+			Source:     source,
+			AimX:       saved.Aim[0],
+			AimY:       saved.Aim[1],
+		}
+		gs.world.Projectiles = append(gs.world.Projectiles, projectile)
+	}
+
+	gs.spawner.Clear()
+	for _, saved := range save.Spawns {
+		stats := saved.Stats
+		stats.Name = saved.Enemy
+		gs.spawner.groups = append(gs.spawner.groups, &spawnState{
+			PlannedGroup: PlannedGroup{
+				Enemy:    stats,
+				Count:    saved.Count,
+				Interval: time.Duration(saved.Interval),
+				Delay:    time.Duration(saved.Delay),
+				Spawn:    saved.Spawn,
+			},
+			remaining: saved.Remaining,
+			wait:      time.Duration(saved.Wait),
+		})
+	}
+	if gs.maze != nil {
+		*gs.maze = *maze
+		gs.paths = paths
+	}
+
+	gs.clock.tick = save.Tick
+	gs.seedRandom(save.Seed, save.RandDraws)
+	gs.lives = save.Lives
+	gs.money = save.Money
+	gs.score = save.Score
+	gs.wave = save.Wave
+	gs.paused = save.Paused
+	gs.nextEnemyID = save.NextEnemyID
+	gs.nextTowerID = save.NextTowerID
+	if save.TowerCosts != nil {
+		gs.towerCosts = save.TowerCosts
+	}
+	return nil
+}
+
+func loadTower(saved savedTower) (*entities.Tower, error) {
+	targeting, ok := entities.TargetingByName(saved.Targeting)
+	if !ok {
+		return nil, fmt.Errorf("unknown targeting strategy %q", saved.Targeting)
+	}
+	if saved.Level < 1 || saved.Level > len(saved.Upgrades)+1 {
+		return nil, fmt.Errorf("level %d out of range", saved.Level)
+	}
+	tower := &entities.Tower{
+		BaseEntity:      entities.BaseEntity{X: saved.Pos[0], Y: saved.Pos[1]},
+		ID:              saved.ID,
+		Range:           saved.Range,
+		Damage:          saved.Damage,
+		DamageType:      saved.DamageType,
+		FireRate:        time.Duration(saved.FireRate),
+		Cooldown:        time.Duration(saved.Cooldown),
+		Level:           saved.Level,
+		Cost:            saved.Cost,
+		Type:            saved.Type,
+		Definition:      string(saved.Definition),
+		CritChance:      saved.CritChance,
+		CritMultiplier:  saved.CritMultiplier,
+		ProjectileSpeed: saved.ProjectileSpeed,
+		Homing:          saved.Homing,
+		Targeting:       targeting,
+		Special:         saved.Special,
+		CanHit:          saved.CanHit,
+		Kills:           saved.Kills,
+	}
+	for _, step := range saved.Upgrades {
+		tower.Upgrades = append(tower.Upgrades, entities.UpgradeStep(step))
+	}
+	if saved.Effect != nil {
+		effect := entities.StatusEffect{
+			Kind:            saved.Effect.Kind,
+			Duration:        time.Duration(saved.Effect.Duration),
+			SpeedMultiplier: saved.Effect.SpeedMultiplier,
+			TickDamage:      saved.Effect.TickDamage,
+			TickInterval:    time.Duration(saved.Effect.TickInterval),
+			DamageType:      saved.Effect.DamageType,
+			Stacking:        saved.Effect.Stacking,
+			MaxStacks:       saved.Effect.MaxStacks,
+		}
+		tower.Effect = &effect
+	}
+	return tower, nil
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"time"
+	"tower-defense/internal/entities"
+)
+
+// Snapshot is a copy of the game as it stood at the end of one tick. Nothing
+// in it is shared with the running game except values the game never
+// modifies once built, so it can be read from any goroutine while the game
+// moves on.
+type Snapshot struct {
+	Tick    uint64
+	Elapsed time.Duration
+	Paused  bool
+	Maze    bool
+
+	Lives  int
+	Money  int
+	Score  int
+	Wave   int
+	Queued int // enemies still waiting to spawn
+
+	Towers      []TowerView
+	Enemies     []EnemyView
+	Projectiles []ProjectileView
+
+	LevelName        string
+	Grid             *Grid
+	Decorations      []Decoration
+	Paths            []PathSegment
+	Path             []entities.BaseEntity // main route, as GetEnemyPath
+	TowerDefinitions []TowerDefinition
+}
+
+type TowerView struct {
+	ID          uint64
+	Type        string
+	X, Y        float64
+	Level       int
+	MaxLevel    int
+	Range       float64
+	Damage      int
+	Targeting   string
+	Kills       int
+	UpgradeCost int
+	SellValue   int
+}
+
+type EnemyView struct {
+	ID        uint64
+	Archetype string
+	X, Y      float64
+	Health    int
+	MaxHealth int
+	Flying    bool
+	Boss      bool
+	Effects   []entities.EffectKind
+	Progress  float64
+	Remaining float64 // distance left to the exit
+}
+
+type ProjectileView struct {
+	X, Y float64
+	Hit  bool
+}
+
+func (e EnemyView) HasEffect(kind entities.EffectKind) bool {
+	for _, effect := range e.Effects {
+		if effect == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// LeadingEnemy returns the enemy closest to the exit, or nil if there are
+// none.
+func (s *Snapshot) LeadingEnemy() *EnemyView {
+	var leader *EnemyView
+	for i := range s.Enemies {
+		if leader == nil || s.Enemies[i].Remaining < leader.Remaining {
+			leader = &s.Enemies[i]
+		}
+	}
+	return leader
+}
+
+// Snapshot copies the state of the game under a single lock, so everything
+// in it belongs to the same tick.
+func (gs *GameState) Snapshot() *Snapshot {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	s := &Snapshot{
+		Tick:    gs.clock.Tick(),
+		Elapsed: gs.clock.Elapsed(),
+		Paused:  gs.paused,
+		Maze:    gs.maze != nil,
+		Lives:   gs.lives,
+		Money:   gs.money,
+		Score:   gs.score,
+		Wave:    gs.wave,
+		Queued:  gs.spawner.Queued(),
+
+		Towers:      make([]TowerView, len(gs.world.Towers)),
+		Enemies:     make([]EnemyView, len(gs.world.Enemies)),
+		Projectiles: make([]ProjectileView, len(gs.world.Projectiles)),
+
+		LevelName: gs.levelName,
+		Grid:      gs.grid.Clone(),
+		// Decorations and path networks are replaced, never modified.
+		Decorations:      gs.decorations,
+		Paths:            gs.paths.Segments,
+		Path:             gs.paths.Route(0, nil),
+		TowerDefinitions: gs.towerDefinitions(),
+	}
+	for i, tower := range gs.world.Towers {
+		targeting := entities.TargetingStrategy(entities.FirstTargeting{})
+		if tower.Targeting != nil {
+			targeting = tower.Targeting
+		}
+		s.Towers[i] = TowerView{
+			ID:          tower.ID,
+			Type:        tower.Type,
+			X:           tower.X,
+			Y:           tower.Y,
+			Level:       tower.Level,
+			MaxLevel:    tower.MaxLevel(),
+			Range:       tower.Range,
+			Damage:      tower.Damage,
+			Targeting:   targeting.Name(),
+			Kills:       tower.Kills,
+			UpgradeCost: tower.GetUpgradeCost(),
+			SellValue:   tower.GetSellValue(),
+		}
+	}
+	for i, enemy := range gs.world.Enemies {
+		s.Enemies[i] = newEnemyView(enemy)
+	}
+	for i, projectile := range gs.world.Projectiles {
+		s.Projectiles[i] = ProjectileView{X: projectile.X, Y: projectile.Y, Hit: projectile.Hit}
+	}
+	return s
+}
+
+func newEnemyView(enemy *entities.Enemy) EnemyView {
+	view := EnemyView{
+		ID:        enemy.ID,
+		Archetype: enemy.Archetype,
+		X:         enemy.X,
+		Y:         enemy.Y,
+		Health:    enemy.Health,
+		MaxHealth: enemy.MaxHealth,
+		Flying:    enemy.Flying,
+		Boss:      enemy.Boss,
+		Progress:  enemy.Progress(),
+		Remaining: enemy.RemainingDistance(),
+	}
+	for _, effect := range enemy.Effects {
+		view.Effects = append(view.Effects, effect.Kind)
+	}
+	return view
+}
@@ -0,0 +1,69 @@
+package core
+
+import "time"
+
+// Spawner releases the enemies of a wave over time. Each group waits for its
+// delay, then releases one enemy every interval until its count runs out.
+type Spawner struct {
+	groups []*spawnState
+}
+
+type spawnState struct {
+	PlannedGroup
+	remaining int
+	wait      time.Duration
+}
+
+func NewSpawner() *Spawner {
+	return &Spawner{}
+}
+
+// Load queues the groups of a wave. Groups from a previous wave that have not
+// finished keep spawning alongside the new ones.
+func (s *Spawner) Load(groups []PlannedGroup) {
+	for _, group := range groups {
+		if group.Count <= 0 {
+			continue
+		}
+		s.groups = append(s.groups, &spawnState{
+			PlannedGroup: group,
+			remaining:    group.Count,
+			wait:         group.Delay,
+		})
+	}
+}
+
+// Update advances the spawner by dt and calls spawn for every enemy that is
+// due, in the order the groups were loaded.
+func (s *Spawner) Update(dt time.Duration, spawn func(PlannedGroup)) {
+	active := s.groups[:0]
+	for _, group := range s.groups {
+		group.wait -= dt
+		for group.remaining > 0 && group.wait <= 0 {
+			spawn(group.PlannedGroup)
+			group.remaining--
+			group.wait += group.Interval
+		}
+		if group.remaining > 0 {
+			active = append(active, group)
+		}
+	}
+	for i := len(active); i < len(s.groups); i++ {
+		s.groups[i] = nil
+	}
+	s.groups = active
+}
+
+// Queued is the number of enemies still waiting to be spawned, counting
+// every member of a swarm.
+func (s *Spawner) Queued() int {
+	queued := 0
+	for _, group := range s.groups {
+		queued += group.remaining * max(group.Enemy.SwarmSize, 1)
+	}
+	return queued
+}
+
+func (s *Spawner) Clear() {
+	s.groups = nil
+}
@@ -0,0 +1,37 @@
+package core
+
+import "tower-defense/internal/systems"
+
+// Names of the systems GameState runs every tick, in order.
+const (
+	SpawningSystem  = "spawning"
+	TargetingSystem = systems.TargetingName
+	CollisionSystem = systems.CollisionName
+	MovementSystem  = systems.MovementName
+	DeathSystem     = systems.DeathName
+	CleanupSystem   = systems.CleanupName
+	WaveSystem      = "waves"
+)
+
+// spawningSystem releases the queued enemies of the current waves.
+type spawningSystem struct{ gs *GameState }
+
+func (spawningSystem) Name() string { return SpawningSystem }
+
+func (s spawningSystem) Update(w *systems.World) {
+	s.gs.spawner.Update(w.Delta, s.gs.spawnEnemy)
+}
+
+// waveSystem starts the next wave once the current one is resolved.
+type waveSystem struct{ gs *GameState }
+
+func (waveSystem) Name() string { return WaveSystem }
+
+func (s waveSystem) Update(w *systems.World) {
+	if s.gs.spawner.Queued() == 0 && len(w.Enemies) == 0 {
+		if s.gs.wave > 0 {
+			s.gs.emit(WaveCleared{Tick: s.gs.clock.Tick(), Wave: s.gs.wave})
+		}
+		s.gs.startNextWave()
+	}
+}
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+	"tower-defense/internal/entities"
+)
+
+// Duration is a time.Duration that reads from JSON as a Go duration string
+// such as "500ms" or "2s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"500ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// EnemyDefinition is one archetype in the enemy catalogue. Its name is the
+// key it is stored under in WaveSet.Enemies.
+type EnemyDefinition struct {
+	Name        string                `json:"-"`
+	Health      int                   `json:"health"`
+	Speed       float64               `json:"speed"`
+	Reward      int                   `json:"reward"`
+	Damage      int                   `json:"damage"`
+	Armor       int                   `json:"armor"`
+	Resistances entities.Resistances  `json:"resistances"`
+	Flying      bool                  `json:"flying"`
+	Boss        bool                  `json:"boss"`
+	SwarmSize   int                   `json:"swarm_size"` // enemies released per spawn, default 1
+	Immunities  []entities.EffectKind `json:"immunities"`
+}
+
+type SpawnGroup struct {
+	Enemy            string   `json:"enemy"`
+	Count            int      `json:"count"`
+	Interval         Duration `json:"interval"`
+	Delay            Duration `json:"delay"`
+	Spawn            int      `json:"spawn"`
+	RewardMultiplier float64  `json:"reward_multiplier"` // zero means 1
+}
+
+type WaveDefinition struct {
+	Groups           []SpawnGroup `json:"groups"`
+	RewardMultiplier float64      `json:"reward_multiplier"` // zero means 1
+}
+
+// WaveSet is the contents of a wave file: named enemy stat blocks and the
+// scripted waves that use them. Waves past the end of the file are generated
+// by EndlessWave.
+type WaveSet struct {
+	Enemies map[string]EnemyDefinition `json:"enemies"`
+	Waves   []WaveDefinition           `json:"waves"`
+}
+
+// PlannedGroup is a spawn group with its enemy stats resolved and reward
+// multipliers applied, ready to be handed to the spawner.
+type PlannedGroup struct {
+	Enemy    EnemyDefinition
+	Count    int
+	Interval time.Duration
+	Delay    time.Duration
+	Spawn    int
+}
+
+func LoadWaves(path string) (*WaveSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWaves(data)
+}
+
+func ParseWaves(data []byte) (*WaveSet, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var waves WaveSet
+	if err := decoder.Decode(&waves); err != nil {
+		return nil, fmt.Errorf("parse waves: %w", err)
+	}
+	if err := waves.Validate(); err != nil {
+		return nil, err
+	}
+	return &waves, nil
+}
+
+func (ws *WaveSet) Validate() error {
+	names := make([]string, 0, len(ws.Enemies))
+	for name := range ws.Enemies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ws.Enemies[name].validate(); err != nil {
+			return fmt.Errorf("enemy %q: %w", name, err)
+		}
+	}
+	for i, wave := range ws.Waves {
+		if len(wave.Groups) == 0 {
+			return fmt.Errorf("wave %d: no groups", i+1)
+		}
+		if wave.RewardMultiplier < 0 {
+			return fmt.Errorf("wave %d: reward_multiplier must not be negative", i+1)
+		}
+		for j, group := range wave.Groups {
+			if err := ws.validateGroup(group); err != nil {
+				return fmt.Errorf("wave %d, group %d: %w", i+1, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (ws *WaveSet) validateGroup(group SpawnGroup) error {
+	if _, ok := ws.Enemies[group.Enemy]; !ok {
+		return fmt.Errorf("unknown enemy %q", group.Enemy)
+	}
+	switch {
+	case group.Count <= 0:
+		return errors.New("count must be positive")
+	case group.Interval < 0 || group.Delay < 0:
+		return errors.New("interval and delay must not be negative")
+	case group.Spawn < 0:
+		return errors.New("spawn must not be negative")
+	case group.RewardMultiplier < 0:
+		return errors.New("reward_multiplier must not be negative")
+	}
+	return nil
+}
+
+func (e EnemyDefinition) validate() error {
+	switch {
+	case e.Health <= 0:
+		return errors.New("health must be positive")
+	case e.Speed <= 0:
+		return errors.New("speed must be positive")
+	case e.Reward < 0 || e.Damage < 0:
+		return errors.New("reward and damage must not be negative")
+	case e.Armor < 0 || e.SwarmSize < 0:
+		return errors.New("armor and swarm_size must not be negative")
+	}
+	for _, kind := range e.Immunities {
+		if !kind.IsValid() {
+			return fmt.Errorf("unknown effect %q in immunities", kind)
+		}
+	}
+	for damageType, resistance := range e.Resistances {
+		if !damageType.IsValid() {
+			return fmt.Errorf("unknown damage type %q in resistances", damageType)
+		}
+		if resistance > 1 {
+			return fmt.Errorf("%s resistance must not exceed 1", damageType)
+		}
+	}
+	return nil
+}
+
+// ValidateSpawns checks that every group refers to one of the map's spawn
+// points.
+func (ws *WaveSet) ValidateSpawns(spawnPoints int) error {
+	for i, wave := range ws.Waves {
+		for j, group := range wave.Groups {
+			if group.Spawn >= spawnPoints {
+				return fmt.Errorf("wave %d, group %d: spawn %d does not exist, map has %d", i+1, j+1, group.Spawn, spawnPoints)
+			}
+		}
+	}
+	return nil
+}
+
+// Plan returns the groups for the given 1-based wave number. A nil WaveSet
+// or a wave past the end of the file falls back to EndlessWave.
+func (ws *WaveSet) Plan(wave int) []PlannedGroup {
+	if ws == nil || wave < 1 || wave > len(ws.Waves) {
+		return EndlessWave(wave)
+	}
+	def := ws.Waves[wave-1]
+	groups := make([]PlannedGroup, len(def.Groups))
+	for i, group := range def.Groups {
+		enemy := ws.Enemies[group.Enemy]
+		enemy.Name = group.Enemy
+		enemy.Reward = scaleReward(enemy.Reward, def.RewardMultiplier, group.RewardMultiplier)
+		groups[i] = PlannedGroup{
+			Enemy:    enemy,
+			Count:    group.Count,
+			Interval: time.Duration(group.Interval),
+			Delay:    time.Duration(group.Delay),
+			Spawn:    group.Spawn,
+		}
+	}
+	return groups
+}
+
+func scaleReward(reward int, multipliers ...float64) int {
+	scaled := float64(reward)
+	for _, m := range multipliers {
+		if m > 0 {
+			scaled *= m
+		}
+	}
+	return int(math.Round(scaled))
+}
+
+// EndlessWave generates a wave from a formula so the game can continue past
+// the scripted waves.
+func EndlessWave(wave int) []PlannedGroup {
+	return []PlannedGroup{{
+		Enemy: EnemyDefinition{
+			Name:   "normal",
+			Health: 50 + wave*10,
+			Speed:  1.0 + float64(wave)/10.0,
+			Reward: 10 + wave,
+			Damage: 1 + wave/5,
+		},
+		Count:    wave * 2,
+		Interval: time.Second / 2,
+	}}
+}
@@ -0,0 +1,47 @@
+package entities
+
+import "math"
+
+type DamageType string
+
+const (
+	DamagePhysical  DamageType = "physical"
+	DamageMagic     DamageType = "magic"
+	DamageExplosive DamageType = "explosive"
+	DamagePierce    DamageType = "pierce"
+)
+
+func (d DamageType) IsValid() bool {
+	switch d {
+	case DamagePhysical, DamageMagic, DamageExplosive, DamagePierce:
+		return true
+	}
+	return false
+}
+
+// Resistances maps a damage type to the fraction of that damage an enemy
+// ignores. 1 makes it immune and negative values make it take extra damage.
+type Resistances map[DamageType]float64
+
+// CalculateDamage returns the damage a hit of the given amount and type deals
+// to e after resistances and armor. Armor is subtracted after resistances and
+// is ignored by pierce damage. A hit always deals at least 1 damage unless
+// the enemy is immune.
+func CalculateDamage(e *Enemy, amount int, damageType DamageType) int {
+	if amount <= 0 {
+		return 0
+	}
+	resistance := e.Resistances[damageType]
+	if resistance >= 1 {
+		return 0
+	}
+	scaled := float64(amount) * (1 - resistance)
+	if damageType != DamagePierce {
+		scaled -= float64(e.Armor)
+	}
+	damage := int(math.Round(scaled))
+	if damage < 1 {
+		return 1
+	}
+	return damage
+}
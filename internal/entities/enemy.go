@@ -4,17 +4,45 @@ import "math"
 
 type Enemy struct {
 	BaseEntity
+	ID        uint64 // set by the game; tells a recycled enemy from the one it replaced
 	Health    int
 	MaxHealth int
-	Speed     float64
+	Speed     float64 // world units per simulation tick
 	Reward    int
 	Damage    int
 	PathIndex int
 	Path      []BaseEntity
+	Distance  float64 // world units travelled along Path
+
+	Archetype   string
+	Armor       int // see CalculateDamage
+	Resistances Resistances
+	Flying      bool
+	Boss        bool
+	Effects     []StatusEffect
+	Immunities  []EffectKind
+
+	// KilledBy is the tower that dealt the killing blow, if any.
+	KilledBy *Tower
+
+	pathLength float64
 }
 
+const (
+	TargetGround = "ground"
+	TargetAir    = "air"
+)
+
 func NewEnemy(health, reward, damage int, speed float64, path []BaseEntity) *Enemy {
-	return &Enemy{
+	e := &Enemy{}
+	e.Init(health, reward, damage, speed, path)
+	return e
+}
+
+// Init overwrites every field of e, so a recycled enemy starts out exactly
+// like one from NewEnemy.
+func (e *Enemy) Init(health, reward, damage int, speed float64, path []BaseEntity) {
+	*e = Enemy{
 		BaseEntity: BaseEntity{X: path[0].X, Y: path[0].Y},
 		Health:     health,
 		MaxHealth:  health,
@@ -23,6 +51,7 @@ func NewEnemy(health, reward, damage int, speed float64, path []BaseEntity) *Ene
 		Damage:     damage,
 		PathIndex:  0,
 		Path:       path,
+		pathLength: pathLength(path),
 	}
 }
 
@@ -34,23 +63,41 @@ func (e *Enemy) TakeDamage(damage int) bool {
 	return e.Health <= 0
 }
 
+// TakeDamageFrom is TakeDamage for damage dealt by a tower, which is
+// credited with the kill if this is the blow that kills the enemy.
+func (e *Enemy) TakeDamageFrom(damage int, source *Tower) bool {
+	wasAlive := e.Health > 0
+	dead := e.TakeDamage(damage)
+	if wasAlive && dead {
+		e.KilledBy = source
+	}
+	return dead
+}
+
 func (e *Enemy) Move() {
 	if e.PathIndex >= len(e.Path)-1 {
 		return
 	}
 
+	speed := e.Speed * e.SpeedMultiplier()
+	if speed <= 0 {
+		return
+	}
+
 	target := e.Path[e.PathIndex+1]
 	dx := target.X - e.X
 	dy := target.Y - e.Y
 	distance := math.Sqrt(dx*dx + dy*dy)
 
-	if distance <= e.Speed {
+	if distance <= speed {
 		e.X = target.X
 		e.Y = target.Y
 		e.PathIndex++
+		e.Distance += distance
 	} else {
-		e.X += (dx / distance) * e.Speed
-		e.Y += (dy / distance) * e.Speed
+		e.X += (dx / distance) * speed
+		e.Y += (dy / distance) * speed
+		e.Distance += speed
 	}
 }
 
@@ -65,3 +112,49 @@ func (e *Enemy) GetReward() int {
 func (e *Enemy) GetDamage() int {
 	return e.Damage
 }
+
+// Progress is how far the enemy has travelled along its path, in world units.
+func (e *Enemy) Progress() float64 {
+	return e.Distance
+}
+
+func (e *Enemy) PathLength() float64 {
+	return e.pathLength
+}
+
+// Reroute sends the enemy along a new path that starts at its current
+// position. Distance already travelled is kept so progress stays comparable
+// between enemies.
+func (e *Enemy) Reroute(path []BaseEntity) {
+	e.Path = path
+	e.PathIndex = 0
+	e.pathLength = e.Distance + pathLength(path)
+}
+
+// SetPathLength overrides the length of the whole route, travelled part
+// included, as when restoring a rerouted enemy from a save.
+func (e *Enemy) SetPathLength(length float64) {
+	e.pathLength = length
+}
+
+func (e *Enemy) RemainingDistance() float64 {
+	remaining := e.pathLength - e.Distance
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func pathLength(path []BaseEntity) float64 {
+	length := 0.0
+	for i := 0; i+1 < len(path); i++ {
+		length += segmentLength(path[i], path[i+1])
+	}
+	return length
+}
+
+func segmentLength(a, b BaseEntity) float64 {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	return math.Sqrt(dx*dx + dy*dy)
+}
@@ -0,0 +1,23 @@
+package entities
+
+// EnemyIndex finds the enemies around a point. Towers and projectiles use it
+// so that they only look at nearby enemies.
+type EnemyIndex interface {
+	// Near appends every enemy within radius of (x, y) to dst, in a stable
+	// order, and returns the extended slice.
+	Near(x, y, radius float64, dst []*Enemy) []*Enemy
+}
+
+// EnemyList is an EnemyIndex that checks every enemy. It is fine for a
+// handful of enemies.
+type EnemyList []*Enemy
+
+func (l EnemyList) Near(x, y, radius float64, dst []*Enemy) []*Enemy {
+	for _, e := range l {
+		dx, dy := x-e.X, y-e.Y
+		if dx*dx+dy*dy <= radius*radius {
+			dst = append(dst, e)
+		}
+	}
+	return dst
+}
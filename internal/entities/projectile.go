@@ -0,0 +1,103 @@
+package entities
+
+import "math"
+
+// HitRadius is how close a non-homing projectile must land to its target's
+// current position to count as a hit.
+const HitRadius = 8.0
+
+type Projectile struct {
+	BaseEntity
+	Speed  float64 // world units per simulation tick
+	Damage int
+	Homing bool
+	Target *Enemy
+	// TargetID is the target's ID at launch. If the enemy is recycled
+	// while the projectile is in flight its ID changes and the shot misses.
+	TargetID uint64
+	Source   *Tower
+	AimX     float64
+	AimY     float64
+	Done     bool
+	Hit      bool
+}
+
+func NewProjectile(source *Tower, target *Enemy, damage int) *Projectile {
+	p := &Projectile{}
+	p.Launch(source, target, damage)
+	return p
+}
+
+// Launch overwrites every field of p with a fresh shot from source, so a
+// recycled projectile behaves exactly like one from NewProjectile.
+func (p *Projectile) Launch(source *Tower, target *Enemy, damage int) {
+	*p = Projectile{
+		BaseEntity: BaseEntity{X: source.X, Y: source.Y},
+		Speed:      source.ProjectileSpeed,
+		Damage:     damage,
+		Homing:     source.Homing,
+		Target:     target,
+		TargetID:   target.ID,
+		Source:     source,
+		AimX:       target.X,
+		AimY:       target.Y,
+	}
+}
+
+// ProjectileAllocator supplies blank projectiles for towers to launch, for
+// example from a pool.
+type ProjectileAllocator interface {
+	Get() *Projectile
+}
+
+type heapProjectiles struct{}
+
+func (heapProjectiles) Get() *Projectile {
+	return &Projectile{}
+}
+
+// Update moves the projectile one tick towards its aim point. Homing
+// projectiles re-aim at their target every tick; the others fly to where the
+// target was when they were fired and miss if it has moved away.
+func (p *Projectile) Update(enemies []*Enemy) {
+	p.UpdateIndexed(EnemyList(enemies))
+}
+
+// UpdateIndexed is Update with the enemies held in an index.
+func (p *Projectile) UpdateIndexed(enemies EnemyIndex) {
+	if p.Done {
+		return
+	}
+	if p.Homing && p.targetAlive() {
+		p.AimX, p.AimY = p.Target.X, p.Target.Y
+	}
+
+	dx := p.AimX - p.X
+	dy := p.AimY - p.Y
+	distance := math.Sqrt(dx*dx + dy*dy)
+	if distance <= p.Speed {
+		p.X, p.Y = p.AimX, p.AimY
+		p.impact(enemies)
+		return
+	}
+	p.X += (dx / distance) * p.Speed
+	p.Y += (dy / distance) * p.Speed
+}
+
+func (p *Projectile) impact(enemies EnemyIndex) {
+	p.Done = true
+	if !p.targetAlive() {
+		return // someone else got there first
+	}
+	dx := p.Target.X - p.X
+	dy := p.Target.Y - p.Y
+	if !p.Homing && dx*dx+dy*dy > HitRadius*HitRadius {
+		return
+	}
+	p.Hit = true
+	p.Source.applyHit(enemies, p.Target, p.Damage)
+}
+
+func (p *Projectile) targetAlive() bool {
+	return p.Target.ID == p.TargetID && p.Target.Health > 0
+}
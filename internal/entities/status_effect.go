@@ -0,0 +1,141 @@
+package entities
+
+import "time"
+
+type EffectKind string
+
+const (
+	EffectSlow   EffectKind = "slow"
+	EffectPoison EffectKind = "poison"
+	EffectBurn   EffectKind = "burn"
+	EffectStun   EffectKind = "stun"
+)
+
+func (k EffectKind) IsValid() bool {
+	switch k {
+	case EffectSlow, EffectPoison, EffectBurn, EffectStun:
+		return true
+	}
+	return false
+}
+
+type StackRule string
+
+const (
+	// StackRefresh merges a reapplied effect into the existing one, keeping
+	// the longer duration and the stronger values.
+	StackRefresh StackRule = "refresh"
+	// StackAdd keeps each application as a separate stack, up to MaxStacks.
+	// Once full, the stack closest to expiring is replaced.
+	StackAdd StackRule = "add"
+)
+
+type StatusEffect struct {
+	Kind            EffectKind
+	Duration        time.Duration // remaining
+	SpeedMultiplier float64       // zero leaves speed unchanged; stun always stops
+	TickDamage      int
+	TickInterval    time.Duration
+	DamageType      DamageType
+	Stacking        StackRule
+	MaxStacks       int
+	Source          *Tower        // credited with kills from damage over time
+	SinceTick       time.Duration // since damage was last dealt
+}
+
+func (e *Enemy) IsImmune(kind EffectKind) bool {
+	for _, immunity := range e.Immunities {
+		if immunity == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyEffect adds effect to the enemy according to its stacking rule and
+// reports whether it took hold.
+func (e *Enemy) ApplyEffect(effect StatusEffect) bool {
+	if effect.Duration <= 0 || e.IsImmune(effect.Kind) {
+		return false
+	}
+	effect.SinceTick = 0
+
+	if effect.Stacking == StackAdd {
+		stacks, oldest := 0, -1
+		for i, existing := range e.Effects {
+			if existing.Kind != effect.Kind {
+				continue
+			}
+			stacks++
+			if oldest < 0 || existing.Duration < e.Effects[oldest].Duration {
+				oldest = i
+			}
+		}
+		if stacks >= max(effect.MaxStacks, 1) {
+			e.Effects[oldest] = effect
+			return true
+		}
+		e.Effects = append(e.Effects, effect)
+		return true
+	}
+
+	for i := range e.Effects {
+		existing := &e.Effects[i]
+		if existing.Kind != effect.Kind {
+			continue
+		}
+		existing.Duration = max(existing.Duration, effect.Duration)
+		existing.TickDamage = max(existing.TickDamage, effect.TickDamage)
+		existing.Source = effect.Source
+		if effect.SpeedMultiplier > 0 && (existing.SpeedMultiplier <= 0 || effect.SpeedMultiplier < existing.SpeedMultiplier) {
+			existing.SpeedMultiplier = effect.SpeedMultiplier
+		}
+		return true
+	}
+	e.Effects = append(e.Effects, effect)
+	return true
+}
+
+// UpdateEffects advances every effect by dt, dealing any damage over time
+// that falls due and dropping effects that have run out.
+func (e *Enemy) UpdateEffects(dt time.Duration) {
+	live := e.Effects[:0]
+	for _, effect := range e.Effects {
+		if effect.TickDamage > 0 && effect.TickInterval > 0 {
+			effect.SinceTick += dt
+			for effect.SinceTick >= effect.TickInterval {
+				effect.SinceTick -= effect.TickInterval
+				e.TakeDamageFrom(CalculateDamage(e, effect.TickDamage, effect.DamageType), effect.Source)
+			}
+		}
+		effect.Duration -= dt
+		if effect.Duration > 0 {
+			live = append(live, effect)
+		}
+	}
+	e.Effects = live
+}
+
+func (e *Enemy) HasEffect(kind EffectKind) bool {
+	for _, effect := range e.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SpeedMultiplier is the factor active effects apply to the enemy's speed.
+// Slows do not stack; the strongest one wins.
+func (e *Enemy) SpeedMultiplier() float64 {
+	multiplier := 1.0
+	for _, effect := range e.Effects {
+		if effect.Kind == EffectStun {
+			return 0
+		}
+		if effect.SpeedMultiplier > 0 && effect.SpeedMultiplier < multiplier {
+			multiplier = effect.SpeedMultiplier
+		}
+	}
+	return multiplier
+}
@@ -0,0 +1,57 @@
+package entities
+
+import "strings"
+
+// TargetingStrategy decides which enemy in range a tower shoots at. The tower
+// fires at the candidate with the highest score.
+type TargetingStrategy interface {
+	Name() string
+	Score(t *Tower, e *Enemy) float64
+}
+
+type FirstTargeting struct{}
+
+func (FirstTargeting) Name() string { return "first" }
+
+func (FirstTargeting) Score(t *Tower, e *Enemy) float64 { return e.Progress() }
+
+type LastTargeting struct{}
+
+func (LastTargeting) Name() string { return "last" }
+
+func (LastTargeting) Score(t *Tower, e *Enemy) float64 { return -e.Progress() }
+
+type StrongestTargeting struct{}
+
+func (StrongestTargeting) Name() string { return "strongest" }
+
+func (StrongestTargeting) Score(t *Tower, e *Enemy) float64 { return float64(e.Health) }
+
+type WeakestTargeting struct{}
+
+func (WeakestTargeting) Name() string { return "weakest" }
+
+func (WeakestTargeting) Score(t *Tower, e *Enemy) float64 { return -float64(e.Health) }
+
+type ClosestTargeting struct{}
+
+func (ClosestTargeting) Name() string { return "closest" }
+
+func (ClosestTargeting) Score(t *Tower, e *Enemy) float64 {
+	dx := t.X - e.X
+	dy := t.Y - e.Y
+	return -(dx*dx + dy*dy)
+}
+
+var targetingStrategies = map[string]TargetingStrategy{
+	"first":     FirstTargeting{},
+	"last":      LastTargeting{},
+	"strongest": StrongestTargeting{},
+	"weakest":   WeakestTargeting{},
+	"closest":   ClosestTargeting{},
+}
+
+func TargetingByName(name string) (TargetingStrategy, bool) {
+	strategy, ok := targetingStrategies[strings.ToLower(name)]
+	return strategy, ok
+}
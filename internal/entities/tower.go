@@ -2,18 +2,59 @@ package entities
 
 import (
 	"errors"
+	"math/rand"
 	"time"
 )
 
+const SpecialAOE = "aoe"
+
+type UpgradeStep struct {
+	Damage             int
+	Range              float64
+	FireRateMultiplier float64
+	Cost               int // zero means Cost * Level
+}
+
+func DefaultUpgrades() []UpgradeStep {
+	return []UpgradeStep{
+		{Damage: 5, Range: 20, FireRateMultiplier: 0.9},
+		{Damage: 5, Range: 20, FireRateMultiplier: 0.9},
+	}
+}
+
 type Tower struct {
 	BaseEntity
-	Range     float64
-	Damage    int
-	FireRate  time.Duration
-	LastFired time.Time
-	Level     int
-	Cost      int
-	Type      string
+	ID         uint64 // set by the game; stays the same while the tower stands
+	Range      float64
+	Damage     int
+	DamageType DamageType
+	FireRate   time.Duration
+	Cooldown   time.Duration
+	Level      int
+	Cost       int
+	Type       string // display name
+	Definition string // ID of the tower definition it was built from, if any
+
+	CritChance     float64
+	CritMultiplier float64
+
+	ProjectileSpeed float64 // zero means the tower hits instantly
+	Homing          bool
+
+	Targeting TargetingStrategy
+	Special   string
+	Upgrades  []UpgradeStep
+
+	// Effect, if set, is applied to every enemy the tower hits directly.
+	Effect *StatusEffect
+
+	// CanHit lists the enemy archetypes the tower can attack, plus the
+	// keywords "ground" and "air". Empty means it can hit anything.
+	CanHit []string
+
+	Kills int // enemies this tower has killed, counted by the game
+
+	nearby []*Enemy // scratch space for range queries
 }
 
 func NewBasicTower(x, y float64) *Tower {
@@ -21,10 +62,17 @@ func NewBasicTower(x, y float64) *Tower {
 		BaseEntity: BaseEntity{X: x, Y: y},
 		Range:      100,
 		Damage:     10,
+		DamageType: DamagePhysical,
 		FireRate:   time.Second,
 		Level:      1,
 		Cost:       50,
 		Type:       "Basic",
+
+		ProjectileSpeed: 6,
+		Homing:          true,
+
+		Targeting: FirstTargeting{},
+		Upgrades:  DefaultUpgrades(),
 	}
 }
 
@@ -33,10 +81,19 @@ func NewSniperTower(x, y float64) *Tower {
 		BaseEntity: BaseEntity{X: x, Y: y},
 		Range:      200,
 		Damage:     30,
+		DamageType: DamagePierce,
 		FireRate:   time.Second * 2,
 		Level:      1,
 		Cost:       100,
 		Type:       "Sniper",
+
+		CritChance:     0.15,
+		CritMultiplier: 2,
+
+		ProjectileSpeed: 30,
+
+		Targeting: StrongestTargeting{},
+		Upgrades:  DefaultUpgrades(),
 	}
 }
 
@@ -45,33 +102,112 @@ func NewAOETower(x, y float64) *Tower {
 		BaseEntity: BaseEntity{X: x, Y: y},
 		Range:      80,
 		Damage:     15,
+		DamageType: DamageExplosive,
 		FireRate:   time.Second * 2,
 		Level:      1,
 		Cost:       150,
 		Type:       "AOE",
+
+		ProjectileSpeed: 5,
+		Homing:          true,
+
+		Targeting: FirstTargeting{},
+		Special:   SpecialAOE,
+		Upgrades:  DefaultUpgrades(),
+		CanHit:    []string{TargetGround},
+	}
+}
+
+func NewFrostTower(x, y float64) *Tower {
+	return &Tower{
+		BaseEntity: BaseEntity{X: x, Y: y},
+		Range:      90,
+		Damage:     3,
+		DamageType: DamageMagic,
+		FireRate:   time.Second,
+		Level:      1,
+		Cost:       120,
+		Type:       "Frost",
+
+		ProjectileSpeed: 6,
+		Homing:          true,
+
+		Targeting: FirstTargeting{},
+		Upgrades:  DefaultUpgrades(),
+		Effect: &StatusEffect{
+			Kind:            EffectSlow,
+			Duration:        2 * time.Second,
+			SpeedMultiplier: 0.5,
+			Stacking:        StackRefresh,
+		},
+	}
+}
+
+func NewPoisonTower(x, y float64) *Tower {
+	return &Tower{
+		BaseEntity: BaseEntity{X: x, Y: y},
+		Range:      110,
+		Damage:     2,
+		DamageType: DamageMagic,
+		FireRate:   time.Second,
+		Level:      1,
+		Cost:       130,
+		Type:       "Poison",
+
+		ProjectileSpeed: 6,
+		Homing:          true,
+
+		Targeting: StrongestTargeting{},
+		Upgrades:  DefaultUpgrades(),
+		Effect: &StatusEffect{
+			Kind:         EffectPoison,
+			Duration:     3 * time.Second,
+			TickDamage:   4,
+			TickInterval: time.Second / 2,
+			DamageType:   DamageMagic,
+			Stacking:     StackAdd,
+			MaxStacks:    5,
+		},
 	}
 }
 
 func (t *Tower) CanFire() bool {
-	return time.Since(t.LastFired) >= t.FireRate
+	return t.Cooldown <= 0
 }
 
 func (t *Tower) Fire() {
-	t.LastFired = time.Now()
+	t.Cooldown = t.FireRate
+}
+
+// Tick advances the tower's cooldown by one simulation step of length dt.
+func (t *Tower) Tick(dt time.Duration) {
+	if t.Cooldown > 0 {
+		t.Cooldown -= dt
+	}
+}
+
+func (t *Tower) MaxLevel() int {
+	return len(t.Upgrades) + 1
 }
 
 func (t *Tower) Upgrade() error {
-	if t.Level >= 3 {
+	if t.Level >= t.MaxLevel() {
 		return errors.New("tower is already at maximum level")
 	}
+	step := t.Upgrades[t.Level-1]
 	t.Level++
-	t.Damage += 5
-	t.Range += 20
-	t.FireRate = time.Duration(float64(t.FireRate) * 0.9)
+	t.Damage += step.Damage
+	t.Range += step.Range
+	if step.FireRateMultiplier > 0 {
+		t.FireRate = time.Duration(float64(t.FireRate) * step.FireRateMultiplier)
+	}
 	return nil
 }
 
 func (t *Tower) GetUpgradeCost() int {
+	if t.Level < t.MaxLevel() && t.Upgrades[t.Level-1].Cost > 0 {
+		return t.Upgrades[t.Level-1].Cost
+	}
 	return t.Cost * t.Level
 }
 
@@ -79,21 +215,100 @@ func (t *Tower) GetSellValue() int {
 	return t.Cost * t.Level / 2
 }
 
-func (t *Tower) Update(enemies []*Enemy) {
+// Update fires at the enemy in range preferred by the tower's targeting
+// strategy and returns the projectile it launched, or nil if it did not fire
+// or hits instantly. Critical hits are rolled from rng; a nil rng disables
+// them.
+func (t *Tower) Update(enemies []*Enemy, dt time.Duration, rng *rand.Rand) *Projectile {
+	return t.UpdateIndexed(EnemyList(enemies), dt, rng, nil)
+}
+
+// UpdateIndexed is Update for enemies held in an index, so that the tower
+// only considers those around it. Projectiles come from projectiles, or are
+// allocated if it is nil.
+func (t *Tower) UpdateIndexed(enemies EnemyIndex, dt time.Duration, rng *rand.Rand, projectiles ProjectileAllocator) *Projectile {
+	t.Tick(dt)
 	if !t.CanFire() {
-		return
+		return nil
+	}
+
+	t.nearby = enemies.Near(t.X, t.Y, t.Range, t.nearby[:0])
+	target := t.SelectTarget(t.nearby)
+	if target == nil {
+		return nil
+	}
+	t.Fire()
+	damage := t.rollDamage(rng)
+	if t.ProjectileSpeed <= 0 {
+		t.applyHit(enemies, target, damage)
+		return nil
+	}
+	if projectiles == nil {
+		projectiles = heapProjectiles{}
+	}
+	p := projectiles.Get()
+	p.Launch(t, target, damage)
+	return p
+}
+
+func (t *Tower) SelectTarget(enemies []*Enemy) *Enemy {
+	strategy := t.Targeting
+	if strategy == nil {
+		strategy = FirstTargeting{}
 	}
 
+	var best *Enemy
+	var bestScore float64
 	for _, enemy := range enemies {
-		if t.IsInRange(enemy) {
-			t.Fire()
-			enemy.TakeDamage(t.Damage)
-			if t.Type == "AOE" {
-				t.DealAOEDamage(enemies, enemy)
-			}
-			break
+		if enemy.Health <= 0 || !t.CanTarget(enemy) || !t.IsInRange(enemy) {
+			continue
+		}
+		score := strategy.Score(t, enemy)
+		if best == nil || score > bestScore {
+			best, bestScore = enemy, score
+		}
+	}
+	return best
+}
+
+func (t *Tower) applyHit(enemies EnemyIndex, target *Enemy, damage int) {
+	target.TakeDamageFrom(CalculateDamage(target, damage, t.DamageType), t)
+	if t.Effect != nil && target.Health > 0 {
+		effect := *t.Effect
+		effect.Source = t
+		target.ApplyEffect(effect)
+	}
+	if t.Special == SpecialAOE {
+		t.nearby = enemies.Near(t.X, t.Y, t.Range, t.nearby[:0])
+		t.DealAOEDamage(t.nearby, target)
+	}
+}
+
+func (t *Tower) rollDamage(rng *rand.Rand) int {
+	if rng == nil || t.CritChance <= 0 {
+		return t.Damage
+	}
+	if rng.Float64() < t.CritChance {
+		return int(float64(t.Damage) * t.CritMultiplier)
+	}
+	return t.Damage
+}
+
+func (t *Tower) CanTarget(e *Enemy) bool {
+	if len(t.CanHit) == 0 {
+		return true
+	}
+	for _, kind := range t.CanHit {
+		switch {
+		case kind == e.Archetype && kind != "":
+			return true
+		case kind == TargetAir && e.Flying:
+			return true
+		case kind == TargetGround && !e.Flying:
+			return true
 		}
 	}
+	return false
 }
 
 func (t *Tower) IsInRange(e *Enemy) bool {
@@ -105,8 +320,8 @@ func (t *Tower) IsInRange(e *Enemy) bool {
 
 func (t *Tower) DealAOEDamage(enemies []*Enemy, target *Enemy) {
 	for _, enemy := range enemies {
-		if enemy != target && t.IsInRange(enemy) {
-			enemy.TakeDamage(t.Damage / 2) // AOE damage is half of the main target
+		if enemy != target && t.CanTarget(enemy) && t.IsInRange(enemy) {
+			enemy.TakeDamageFrom(CalculateDamage(enemy, t.Damage/2, t.DamageType), t) // AOE damage is half of the main target
 		}
 	}
 }
@@ -0,0 +1,143 @@
+package input
+
+import (
+	"errors"
+	"tower-defense/internal/core"
+	"unicode"
+)
+
+// Action is something a key asks of the game loop rather than of the game.
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionSave
+	ActionLoad
+	ActionQuit
+)
+
+var (
+	ErrNoTower      = errors.New("no tower under the cursor")
+	ErrNoTowerTypes = errors.New("no towers to build")
+	ErrUnknownKey   = errors.New("unknown key")
+)
+
+// targetingCycle is the order T steps through targeting strategies.
+var targetingCycle = []string{"first", "last", "strongest", "weakest", "closest"}
+
+// Controller turns key presses into game commands. It keeps a cursor on the
+// map, where towers are built, upgraded and sold, and the tower type chosen
+// with the number keys.
+//
+//	arrows  move the cursor      1-9  choose a tower type
+//	b       build                u    upgrade
+//	s       sell                 t    change targeting
+//	p       pause                n    call the next wave
+//	w       save                 l    load
+//	q       quit
+type Controller struct {
+	Col, Row int
+	Selected int // index into the tower definitions
+}
+
+// NewController puts the cursor in the middle of the grid.
+func NewController(grid *core.Grid) *Controller {
+	return &Controller{Col: grid.Cols / 2, Row: grid.Rows / 2}
+}
+
+// Cursor returns the centre of the tile under the cursor in world units.
+func (c *Controller) Cursor(grid *core.Grid) (float64, float64) {
+	return grid.TileCenter(c.Col, c.Row)
+}
+
+// Handle works out what a key press asks for, given the game as it stands.
+// It returns a command to submit, an action for the game loop, or an error
+// explaining why the key does nothing here.
+func (c *Controller) Handle(key Key, s *core.Snapshot) (core.Command, Action, error) {
+	switch key {
+	case KeyUp:
+		c.move(s.Grid, 0, -1)
+		return nil, ActionNone, nil
+	case KeyDown:
+		c.move(s.Grid, 0, 1)
+		return nil, ActionNone, nil
+	case KeyLeft:
+		c.move(s.Grid, -1, 0)
+		return nil, ActionNone, nil
+	case KeyRight:
+		c.move(s.Grid, 1, 0)
+		return nil, ActionNone, nil
+	case KeyCtrlC:
+		return nil, ActionQuit, nil
+	}
+	if key >= '1' && key <= '9' {
+		i := int(key - '1')
+		if i >= len(s.TowerDefinitions) {
+			return nil, ActionNone, ErrUnknownKey
+		}
+		c.Selected = i
+		return nil, ActionNone, nil
+	}
+
+	switch unicode.ToLower(rune(key)) {
+	case 'b':
+		if c.Selected >= len(s.TowerDefinitions) {
+			return nil, ActionNone, ErrNoTowerTypes
+		}
+		x, y := c.Cursor(s.Grid)
+		return core.BuildCommand{Tower: s.TowerDefinitions[c.Selected].ID, X: x, Y: y}, ActionNone, nil
+	case 'u':
+		tower, err := c.towerAt(s)
+		if err != nil {
+			return nil, ActionNone, err
+		}
+		return core.UpgradeCommand{Tower: s.Towers[tower].ID}, ActionNone, nil
+	case 's':
+		tower, err := c.towerAt(s)
+		if err != nil {
+			return nil, ActionNone, err
+		}
+		return core.SellCommand{Tower: s.Towers[tower].ID}, ActionNone, nil
+	case 't':
+		tower, err := c.towerAt(s)
+		if err != nil {
+			return nil, ActionNone, err
+		}
+		return core.SetTargetingCommand{Tower: s.Towers[tower].ID, Strategy: nextTargeting(s.Towers[tower].Targeting)}, ActionNone, nil
+	case 'p':
+		return core.PauseCommand{}, ActionNone, nil
+	case 'n':
+		return core.CallWaveCommand{}, ActionNone, nil
+	case 'w':
+		return nil, ActionSave, nil
+	case 'l':
+		return nil, ActionLoad, nil
+	case 'q':
+		return nil, ActionQuit, nil
+	}
+	return nil, ActionNone, ErrUnknownKey
+}
+
+func (c *Controller) move(grid *core.Grid, dCol, dRow int) {
+	c.Col = max(0, min(c.Col+dCol, grid.Cols-1))
+	c.Row = max(0, min(c.Row+dRow, grid.Rows-1))
+}
+
+// towerAt returns the index of the tower under the cursor.
+func (c *Controller) towerAt(s *core.Snapshot) (int, error) {
+	for i, tower := range s.Towers {
+		if col, row := s.Grid.TileAt(tower.X, tower.Y); col == c.Col && row == c.Row {
+			return i, nil
+		}
+	}
+	return -1, ErrNoTower
+}
+
+func nextTargeting(current string) string {
+	for i, name := range targetingCycle {
+		if name == current {
+			return targetingCycle[(i+1)%len(targetingCycle)]
+		}
+	}
+	return targetingCycle[0]
+}
@@ -0,0 +1,74 @@
+package input
+
+import (
+	"bufio"
+	"io"
+)
+
+// Key is a key press: the character typed, or one of the Key constants for
+// keys that send escape sequences.
+type Key rune
+
+const (
+	KeyUp Key = -1 - iota
+	KeyDown
+	KeyRight
+	KeyLeft
+)
+
+const (
+	KeyEscape Key = 0x1b
+	KeyCtrlC  Key = 0x03
+)
+
+// ReadKeys reads key presses from r in its own goroutine and sends them to
+// the returned channel, which is closed when r is exhausted. r is normally
+// a terminal in raw mode, see MakeRaw, but any stream of keystrokes will do.
+func ReadKeys(r io.Reader) <-chan Key {
+	keys := make(chan Key)
+	go func() {
+		defer close(keys)
+		br := bufio.NewReader(r)
+		for {
+			key, err := readKey(br)
+			if err != nil {
+				return
+			}
+			keys <- key
+		}
+	}()
+	return keys
+}
+
+// readKey decodes one key press. An escape followed at once by '[' and a
+// letter is an arrow key; an escape on its own is just that. A terminal
+// sends the whole sequence in one write, so anything not yet buffered
+// belongs to a later key press.
+func readKey(br *bufio.Reader) (Key, error) {
+	r, _, err := br.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if Key(r) != KeyEscape || br.Buffered() < 2 {
+		return Key(r), nil
+	}
+	seq, err := br.Peek(2)
+	if err != nil || seq[0] != '[' {
+		return KeyEscape, nil
+	}
+	var key Key
+	switch seq[1] {
+	case 'A':
+		key = KeyUp
+	case 'B':
+		key = KeyDown
+	case 'C':
+		key = KeyRight
+	case 'D':
+		key = KeyLeft
+	default:
+		return KeyEscape, nil
+	}
+	br.Discard(2)
+	return key, nil
+}
@@ -0,0 +1,39 @@
+package input
+
+import (
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Terminal is a terminal switched to raw mode by MakeRaw.
+type Terminal struct {
+	fd    int
+	state *term.State
+	once  sync.Once
+}
+
+// MakeRaw switches the terminal on f to raw mode: key presses are delivered
+// at once and unechoed, and nothing is translated, so Ctrl-C arrives as
+// KeyCtrlC rather than raising an interrupt and output lines must end in
+// "\r\n". It fails if f is not a terminal. Call Restore when done,
+// including on the way out of a panic.
+func MakeRaw(f *os.File) (*Terminal, error) {
+	fd := int(f.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return &Terminal{fd: fd, state: state}, nil
+}
+
+// Restore puts the terminal back the way MakeRaw found it. Only the first
+// call does anything, so it is safe to both defer it and call it early.
+func (t *Terminal) Restore() error {
+	var err error
+	t.once.Do(func() {
+		err = term.Restore(t.fd, t.state)
+	})
+	return err
+}
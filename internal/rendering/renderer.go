@@ -14,15 +14,34 @@ const (
 	borderChar     = '█'
 	cornerChar     = '█'
 	enemyChar      = 'E'
+	flyingChar     = 'F'
+	bossChar       = 'B'
+	swarmChar      = 'e'
 	towerChar      = 'T'
+	blockedChar    = '#'
+	waterChar      = '~'
 	projectileChar = '•'
+	cursorLeft     = '['
+	cursorRight    = ']'
 	sidebarWidth   = 25
-	hudHeight      = 3
+	hudHeight      = 4 // border, title, message and border
+	titleRow       = 1
+	messageRow     = 2
+	exitWarning    = 150 // world units from the exit
 )
 
 type Renderer struct {
-	mu     sync.Mutex
-	buffer [][]string
+	mu          sync.Mutex
+	buffer      [][]string
+	worldWidth  float64
+	worldHeight float64
+	kills       int
+	leaks       int
+	message     string // latest game event worth telling the player
+
+	showCursor       bool
+	cursorX, cursorY float64
+	selected         int // tower type picked for building
 }
 
 func NewRenderer() *Renderer {
@@ -30,22 +49,74 @@ func NewRenderer() *Renderer {
 	for i := range buffer {
 		buffer[i] = make([]string, gameWidth)
 	}
-	return &Renderer{buffer: buffer}
+	return &Renderer{buffer: buffer, worldWidth: 800, worldHeight: 600}
 }
 
+// Render draws one frame from a snapshot of the game, so the frame never
+// mixes two ticks.
 func (r *Renderer) Render(gs *core.GameState) {
+	r.RenderSnapshot(gs.Snapshot())
+}
+
+func (r *Renderer) RenderSnapshot(s *core.Snapshot) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.worldWidth, r.worldHeight = s.Grid.WorldSize()
 	r.clearBuffer()
-	r.drawGameArea(gs)
-	r.drawWindow()
-	r.drawHUD(gs)
-	r.drawSidebar(gs)
+	r.drawGameArea(s)
+	r.drawWindow(s.LevelName)
+	r.drawHUD(s)
+	r.drawSidebar(s)
 	r.display()
 }
 
-func (r *Renderer) drawWindow() {
+// Watch follows the game's events to keep the sidebar stats and the message
+// line up to date.
+func (r *Renderer) Watch(bus *core.EventBus) {
+	bus.Subscribe("", func(event core.Event) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		switch e := event.(type) {
+		case core.EnemyKilled:
+			r.kills++
+		case core.EnemyLeaked:
+			r.leaks++
+		case core.WaveStarted:
+			r.message = fmt.Sprintf("Wave %d: %d enemies incoming", e.Wave, e.Enemies)
+		case core.WaveCleared:
+			r.message = fmt.Sprintf("Wave %d cleared", e.Wave)
+		case core.TowerBuilt:
+			r.message = fmt.Sprintf("Built %s tower", e.Name)
+		case core.TowerUpgraded:
+			r.message = fmt.Sprintf("%s tower upgraded to level %d", e.Name, e.Level)
+		case core.TowerSold:
+			r.message = fmt.Sprintf("Sold %s tower for $%d", e.Name, e.Refund)
+		case core.GameOver:
+			r.message = "Game over"
+		}
+	})
+}
+
+// Notify shows msg on the message line until the next game event replaces
+// it.
+func (r *Renderer) Notify(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.message = msg
+}
+
+// SetCursor marks the tile the player is pointing at and the tower type
+// they have picked.
+func (r *Renderer) SetCursor(x, y float64, selected int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.showCursor = true
+	r.cursorX, r.cursorY = x, y
+	r.selected = selected
+}
+
+func (r *Renderer) drawWindow(levelName string) {
 	// Draw vertical borders
 	for y := 0; y < gameHeight; y++ {
 		r.buffer[y][0] = string(borderChar)
@@ -67,16 +138,35 @@ func (r *Renderer) drawWindow() {
 
 	// Draw title
 	title := " Tower Defense "
+	if levelName != "" {
+		title = fmt.Sprintf(" Tower Defense - %s ", levelName)
+	}
 	titleStart := (gameWidth - len(title)) / 2
-	for i, ch := range title {
-		r.buffer[1][titleStart+i] = string(ch)
+	r.drawText(titleRow, titleStart, title)
+}
+
+func (r *Renderer) drawGameArea(s *core.Snapshot) {
+	r.drawTerrain(s.Grid)
+	r.drawDecorations(s.Decorations, s.Grid)
+	for _, segment := range s.Paths {
+		r.drawPath(segment.Points)
+	}
+	r.drawTowers(s.Towers)
+	r.drawEnemies(s.Enemies)
+	r.drawProjectiles(s.Projectiles)
+	if r.showCursor {
+		r.drawCursor()
 	}
 }
 
-func (r *Renderer) drawGameArea(gs *core.GameState) {
-	r.drawPath(gs.GetEnemyPath())
-	r.drawTowers(gs.GetTowers())
-	r.drawEnemies(gs.GetEnemies())
+func (r *Renderer) drawCursor() {
+	screenX, screenY := r.worldToScreen(r.cursorX, r.cursorY)
+	if r.isInBounds(screenX-1, screenY) {
+		r.buffer[screenY][screenX-1] = string(cursorLeft)
+	}
+	if r.isInBounds(screenX+1, screenY) {
+		r.buffer[screenY][screenX+1] = string(cursorRight)
+	}
 }
 
 func (r *Renderer) clearBuffer() {
@@ -87,6 +177,35 @@ func (r *Renderer) clearBuffer() {
 	}
 }
 
+func (r *Renderer) drawTerrain(grid *core.Grid) {
+	for row := 0; row < grid.Rows; row++ {
+		for col := 0; col < grid.Cols; col++ {
+			var ch rune
+			switch grid.At(col, row) {
+			case core.TerrainBlocked:
+				ch = blockedChar
+			case core.TerrainWater:
+				ch = waterChar
+			default:
+				continue
+			}
+			screenX, screenY := r.worldToScreen(grid.TileCenter(col, row))
+			if r.isInBounds(screenX, screenY) {
+				r.buffer[screenY][screenX] = string(ch)
+			}
+		}
+	}
+}
+
+func (r *Renderer) drawDecorations(decorations []core.Decoration, grid *core.Grid) {
+	for _, decoration := range decorations {
+		screenX, screenY := r.worldToScreen(grid.TileCenter(decoration.Tile[0], decoration.Tile[1]))
+		if r.isInBounds(screenX, screenY) {
+			r.buffer[screenY][screenX] = decoration.Glyph
+		}
+	}
+}
+
 func (r *Renderer) drawPath(enemyPath []entities.BaseEntity) {
 	if len(enemyPath) < 2 {
 		return // Need at least two points to draw a path
@@ -143,46 +262,106 @@ func abs(x int) int {
 	return x
 }
 
-func (r *Renderer) drawTowers(towers []*entities.Tower) {
+func (r *Renderer) drawTowers(towers []core.TowerView) {
 	for _, tower := range towers {
-		x, y := tower.GetPosition()
-		screenX, screenY := r.worldToScreen(x, y)
+		screenX, screenY := r.worldToScreen(tower.X, tower.Y)
 		if r.isInBounds(screenX, screenY) {
 			r.buffer[screenY][screenX] = string(towerChar)
 		}
 	}
 }
 
-func (r *Renderer) drawEnemies(enemies []*entities.Enemy) {
+func (r *Renderer) drawEnemies(enemies []core.EnemyView) {
 	for _, enemy := range enemies {
-		x, y := enemy.GetPosition()
-		screenX, screenY := r.worldToScreen(x, y)
+		screenX, screenY := r.worldToScreen(enemy.X, enemy.Y)
 		if r.isInBounds(screenX, screenY) {
-			r.buffer[screenY][screenX] = string(enemyChar)
+			glyph := string(enemyGlyph(enemy))
+			if color := effectColor(enemy); color != "" {
+				glyph = color + glyph + colorReset
+			}
+			r.buffer[screenY][screenX] = glyph
 		}
 	}
 }
 
-func (r *Renderer) drawHUD(gs *core.GameState) {
-	hudInfo := fmt.Sprintf("Wave: %d | Lives: %d | Money: %d", gs.GetWave(), gs.GetLives(), gs.GetMoney())
+func enemyGlyph(enemy core.EnemyView) rune {
+	switch {
+	case enemy.Boss:
+		return bossChar
+	case enemy.Flying:
+		return flyingChar
+	case enemy.Archetype == "swarm":
+		return swarmChar
+	default:
+		return enemyChar
+	}
+}
+
+const colorReset = "\033[0m"
+
+// effectColor marks enemies under a status effect. Stun takes precedence,
+// then burn, poison and slow.
+func effectColor(enemy core.EnemyView) string {
+	switch {
+	case enemy.HasEffect(entities.EffectStun):
+		return "\033[33m"
+	case enemy.HasEffect(entities.EffectBurn):
+		return "\033[31m"
+	case enemy.HasEffect(entities.EffectPoison):
+		return "\033[32m"
+	case enemy.HasEffect(entities.EffectSlow):
+		return "\033[36m"
+	default:
+		return ""
+	}
+}
+
+func (r *Renderer) drawProjectiles(projectiles []core.ProjectileView) {
+	for _, projectile := range projectiles {
+		screenX, screenY := r.worldToScreen(projectile.X, projectile.Y)
+		if r.isInBounds(screenX, screenY) && r.buffer[screenY][screenX] == " " {
+			r.buffer[screenY][screenX] = string(projectileChar)
+		}
+	}
+}
+
+func (r *Renderer) drawHUD(s *core.Snapshot) {
+	hudInfo := fmt.Sprintf("Wave: %d | Lives: %d | Money: %d | Score: %d | Incoming: %d", s.Wave, s.Lives, s.Money, s.Score, s.Queued)
+	if leader := s.LeadingEnemy(); leader != nil && leader.Remaining < exitWarning {
+		hudInfo += fmt.Sprintf(" | ! Enemy %.0f from exit", leader.Remaining)
+	}
 	r.drawText(gameHeight-1, 1, hudInfo)
+	if r.message != "" {
+		r.drawText(messageRow, 1, r.message)
+	}
 }
 
-func (r *Renderer) drawSidebar(gs *core.GameState) {
+func (r *Renderer) drawSidebar(s *core.Snapshot) {
 	sidebarX := gameWidth - sidebarWidth + 1
-	r.drawText(3, sidebarX, "Tower Types:")
-	r.drawText(4, sidebarX, "1. Basic Tower  $50")
-	r.drawText(5, sidebarX, "2. Sniper Tower $100")
-	r.drawText(6, sidebarX, "3. AOE Tower    $150")
-
-	r.drawText(8, sidebarX, "Controls:")
-	r.drawText(9, sidebarX, "B: Build mode")
-	r.drawText(10, sidebarX, "U: Upgrade tower")
-	r.drawText(11, sidebarX, "S: Sell tower")
-	r.drawText(12, sidebarX, "P: Pause game")
+	row := hudHeight
+	r.drawText(row, sidebarX, "Tower Types:")
+	for i, def := range s.TowerDefinitions {
+		row++
+		marker := " "
+		if r.showCursor && i == r.selected {
+			marker = ">"
+		}
+		r.drawText(row, sidebarX, fmt.Sprintf("%s%d. %-13s $%d", marker, i+1, def.Name+" Tower", def.Cost))
+	}
 
-	r.drawText(14, sidebarX, "Stats:")
-	r.drawText(16, sidebarX, fmt.Sprintf("Towers Built: %d", len(gs.GetTowers())))
+	row += 2
+	r.drawText(row, sidebarX, "Controls:")
+	r.drawText(row+1, sidebarX, "Arrows: move  1-9: pick")
+	r.drawText(row+2, sidebarX, "B: build   U: upgrade")
+	r.drawText(row+3, sidebarX, "S: sell    T: targeting")
+	r.drawText(row+4, sidebarX, "P: pause   N: next wave")
+	r.drawText(row+5, sidebarX, "W: save    L: load")
+	r.drawText(row+6, sidebarX, "Q: quit")
+
+	row += 8
+	r.drawText(row, sidebarX, "Stats:")
+	r.drawText(row+1, sidebarX, fmt.Sprintf("Kills: %d  Leaks: %d", r.kills, r.leaks))
+	r.drawText(row+2, sidebarX, fmt.Sprintf("Towers Built: %d", len(s.Towers)))
 }
 
 func (r *Renderer) drawText(y, x int, text string) {
@@ -200,14 +379,14 @@ func (r *Renderer) display() {
 	sb.Grow(gameWidth * gameHeight) // Pre-allocate buffer
 	for _, row := range r.buffer {
 		sb.WriteString(strings.Join(row, ""))
-		sb.WriteRune('\n')
+		sb.WriteString("\r\n") // raw terminals do not return the carriage on \n
 	}
 	fmt.Print(sb.String())
 }
 
 func (r *Renderer) worldToScreen(x, y float64) (int, int) {
-	screenX := int(x * float64(gameWidth-sidebarWidth-2) / 800)
-	screenY := int(y * float64(gameHeight-hudHeight-3) / 600)
+	screenX := int(x * float64(gameWidth-sidebarWidth-2) / r.worldWidth)
+	screenY := int(y * float64(gameHeight-hudHeight-3) / r.worldHeight)
 
 	// Ensure we're not writing to the border
 	screenX = max(1, min(screenX, gameWidth-sidebarWidth-2))
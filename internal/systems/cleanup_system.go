@@ -0,0 +1,22 @@
+package systems
+
+const CleanupName = "cleanup"
+
+// Cleanup removes the enemies that have reached the exit.
+type Cleanup struct{}
+
+func (Cleanup) Name() string { return CleanupName }
+
+func (Cleanup) Update(w *World) {
+	for i := 0; i < len(w.Enemies); i++ {
+		enemy := w.Enemies[i]
+		if !enemy.HasReachedEnd() {
+			continue
+		}
+		if w.OnLeak != nil {
+			w.OnLeak(enemy)
+		}
+		w.RemoveEnemy(i)
+		i--
+	}
+}
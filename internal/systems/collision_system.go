@@ -0,0 +1,25 @@
+package systems
+
+const CollisionName = "collision"
+
+// Collision flies every projectile one tick and recycles those that have
+// landed or missed.
+type Collision struct{}
+
+func (Collision) Name() string { return CollisionName }
+
+func (Collision) Update(w *World) {
+	live := w.Projectiles[:0]
+	for _, projectile := range w.Projectiles {
+		projectile.UpdateIndexed(w.EnemyIndex)
+		if projectile.Done {
+			w.ShotPool.Put(projectile)
+		} else {
+			live = append(live, projectile)
+		}
+	}
+	for i := len(live); i < len(w.Projectiles); i++ {
+		w.Projectiles[i] = nil
+	}
+	w.Projectiles = live
+}
@@ -0,0 +1,23 @@
+package systems
+
+const DeathName = "death"
+
+// Death removes the enemies that were killed during the tick, whether by a
+// hit, splash damage or an effect.
+type Death struct{}
+
+func (Death) Name() string { return DeathName }
+
+func (Death) Update(w *World) {
+	for i := 0; i < len(w.Enemies); i++ {
+		enemy := w.Enemies[i]
+		if enemy.Health > 0 {
+			continue
+		}
+		if w.OnKill != nil {
+			w.OnKill(enemy)
+		}
+		w.RemoveEnemy(i)
+		i--
+	}
+}
@@ -0,0 +1,16 @@
+package systems
+
+const MovementName = "movement"
+
+// Movement runs each enemy's status effects and then walks it along its
+// path.
+type Movement struct{}
+
+func (Movement) Name() string { return MovementName }
+
+func (Movement) Update(w *World) {
+	for _, enemy := range w.Enemies {
+		enemy.UpdateEffects(w.Delta)
+		enemy.Move()
+	}
+}
@@ -0,0 +1,22 @@
+package systems
+
+const TargetingName = "targeting"
+
+// Targeting rebuilds the enemy index and lets every tower fire. Enemies only
+// move after towers and projectiles have acted, so the index stays valid for
+// the rest of the tick's combat.
+type Targeting struct{}
+
+func (Targeting) Name() string { return TargetingName }
+
+func (Targeting) Update(w *World) {
+	w.EnemyIndex.Clear()
+	for _, enemy := range w.Enemies {
+		w.EnemyIndex.Insert(enemy, enemy.X, enemy.Y)
+	}
+	for _, tower := range w.Towers {
+		if projectile := tower.UpdateIndexed(w.EnemyIndex, w.Delta, w.Rng, w.ShotPool); projectile != nil {
+			w.Projectiles = append(w.Projectiles, projectile)
+		}
+	}
+}
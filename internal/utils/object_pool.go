@@ -0,0 +1,53 @@
+package utils
+
+// Pool recycles objects of type T to save the garbage collector from
+// short-lived allocations. Objects are zeroed when they are put back, so
+// nothing from one use can leak into the next. A Pool is not safe for
+// concurrent use.
+type Pool[T any] struct {
+	free []*T
+	out  map[*T]struct{}
+}
+
+func NewPool[T any]() *Pool[T] {
+	return &Pool[T]{out: make(map[*T]struct{})}
+}
+
+// Get returns a zeroed object, reusing one that was put back if there is
+// one.
+func (p *Pool[T]) Get() *T {
+	var x *T
+	if n := len(p.free); n > 0 {
+		x = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+	} else {
+		x = new(T)
+	}
+	p.out[x] = struct{}{}
+	return x
+}
+
+// Put zeroes x and keeps it for a later Get. Objects the pool did not hand
+// out, or that were already put back, are ignored so that one object can
+// never be handed out twice; Put reports whether x was taken.
+func (p *Pool[T]) Put(x *T) bool {
+	if _, ok := p.out[x]; !ok {
+		return false
+	}
+	delete(p.out, x)
+	var zero T
+	*x = zero
+	p.free = append(p.free, x)
+	return true
+}
+
+// InUse is the number of objects handed out and not yet put back.
+func (p *Pool[T]) InUse() int {
+	return len(p.out)
+}
+
+// Idle is the number of objects waiting to be reused.
+func (p *Pool[T]) Idle() int {
+	return len(p.free)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import "math"
+
+// SpatialHash buckets items on a uniform grid of square cells so that range
+// queries only look at the cells around the query point. It is meant to be
+// cleared and refilled every tick. A SpatialHash is not safe for concurrent
+// use.
+type SpatialHash[T any] struct {
+	cellSize float64
+	cells    map[cell][]entry[T]
+	count    int
+}
+
+type cell struct{ x, y int }
+
+type entry[T any] struct {
+	item T
+	x, y float64
+}
+
+// NewSpatialHash returns an empty hash. Cells around half the typical query
+// radius work best.
+func NewSpatialHash[T any](cellSize float64) *SpatialHash[T] {
+	return &SpatialHash[T]{
+		cellSize: cellSize,
+		cells:    make(map[cell][]entry[T]),
+	}
+}
+
+// Clear empties the hash, keeping its buckets for reuse.
+func (h *SpatialHash[T]) Clear() {
+	for key, bucket := range h.cells {
+		h.cells[key] = bucket[:0]
+	}
+	h.count = 0
+}
+
+func (h *SpatialHash[T]) Insert(item T, x, y float64) {
+	key := h.cellAt(x, y)
+	h.cells[key] = append(h.cells[key], entry[T]{item: item, x: x, y: y})
+	h.count++
+}
+
+func (h *SpatialHash[T]) Len() int {
+	return h.count
+}
+
+// Near appends to dst every item within radius of (x, y) and returns the
+// extended slice. Items come out cell by cell, row by row, and in insertion
+// order within a cell, so the same contents always give the same order.
+func (h *SpatialHash[T]) Near(x, y, radius float64, dst []T) []T {
+	lo := h.cellAt(x-radius, y-radius)
+	hi := h.cellAt(x+radius, y+radius)
+	radiusSquared := radius * radius
+
+	for cy := lo.y; cy <= hi.y; cy++ {
+		for cx := lo.x; cx <= hi.x; cx++ {
+			for _, e := range h.cells[cell{cx, cy}] {
+				dx, dy := x-e.x, y-e.y
+				if dx*dx+dy*dy <= radiusSquared {
+					dst = append(dst, e.item)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func (h *SpatialHash[T]) cellAt(x, y float64) cell {
+	return cell{int(math.Floor(x / h.cellSize)), int(math.Floor(y / h.cellSize))}
+}
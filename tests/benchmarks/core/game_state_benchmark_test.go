@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+	"tower-defense/internal/core"
+)
+
+// BenchmarkEndlessRun plays waves back to back with a ring of towers along
+// the path, reporting the allocations made per simulated tick.
+func BenchmarkEndlessRun(b *testing.B) {
+	gs := core.NewGameState(core.WithSeed(1))
+	gs.SetMoney(1 << 30)
+	for x := 50.0; x < 800; x += 100 {
+		gs.AddTower(core.BasicTower, x, 275)
+		gs.AddTower(core.SniperTower, x, 75)
+		gs.AddTower(core.AOETower, x, 525)
+	}
+	gs.SetLives(1 << 30)
+	gs.NextWave()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gs.Update()
+	}
+}
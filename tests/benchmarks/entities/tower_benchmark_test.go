@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+	"tower-defense/internal/entities"
+	"tower-defense/internal/utils"
+)
+
+const (
+	benchTowers  = 100
+	benchEnemies = 2000
+)
+
+// benchWorld scatters towers and enemies over the 800x600 playfield. The
+// towers fire every tick without killing anything, so each tick does the
+// same amount of targeting work.
+func benchWorld() ([]*entities.Tower, []*entities.Enemy) {
+	rng := rand.New(rand.NewSource(1))
+	towers := make([]*entities.Tower, benchTowers)
+	for i := range towers {
+		tower := entities.NewBasicTower(rng.Float64()*800, rng.Float64()*600)
+		tower.FireRate = 0
+		tower.ProjectileSpeed = 0
+		towers[i] = tower
+	}
+	enemies := make([]*entities.Enemy, benchEnemies)
+	for i := range enemies {
+		x, y := rng.Float64()*800, rng.Float64()*600
+		enemies[i] = entities.NewEnemy(1<<30, 0, 0, 1, []entities.BaseEntity{{X: x, Y: y}, {X: x + 1, Y: y}})
+	}
+	return towers, enemies
+}
+
+func BenchmarkTowerTargetingLinear(b *testing.B) {
+	towers, enemies := benchWorld()
+	var list entities.EnemyIndex = entities.EnemyList(enemies)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, tower := range towers {
+			tower.UpdateIndexed(list, time.Second/60, nil, nil)
+		}
+	}
+}
+
+func BenchmarkTowerTargetingSpatialHash(b *testing.B) {
+	towers, enemies := benchWorld()
+	hash := utils.NewSpatialHash[*entities.Enemy](50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// The index is rebuilt every tick, so its cost counts.
+		hash.Clear()
+		for _, enemy := range enemies {
+			hash.Insert(enemy, enemy.X, enemy.Y)
+		}
+		for _, tower := range towers {
+			tower.UpdateIndexed(hash, time.Second/60, nil, nil)
+		}
+	}
+}
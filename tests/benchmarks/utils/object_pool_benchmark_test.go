@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+	"tower-defense/internal/entities"
+	"tower-defense/internal/utils"
+)
+
+var (
+	benchPath = []entities.BaseEntity{{X: 0, Y: 0}, {X: 800, Y: 0}}
+	sink      *entities.Enemy
+	shotSink  *entities.Projectile
+)
+
+// A wave's worth of enemies is created and then thrown away, as happens
+// every wave of an endless run.
+const waveSize = 50
+
+func BenchmarkEnemyNew(b *testing.B) {
+	b.ReportAllocs()
+	wave := make([]*entities.Enemy, waveSize)
+	for i := 0; i < b.N; i++ {
+		for j := range wave {
+			wave[j] = entities.NewEnemy(100, 10, 1, 1, benchPath)
+		}
+		sink = wave[0]
+	}
+}
+
+func BenchmarkEnemyPool(b *testing.B) {
+	b.ReportAllocs()
+	pool := utils.NewPool[entities.Enemy]()
+	wave := make([]*entities.Enemy, waveSize)
+	for i := 0; i < b.N; i++ {
+		for j := range wave {
+			wave[j] = pool.Get()
+			wave[j].Init(100, 10, 1, 1, benchPath)
+		}
+		sink = wave[0]
+		for _, enemy := range wave {
+			pool.Put(enemy)
+		}
+	}
+}
+
+func BenchmarkProjectileNew(b *testing.B) {
+	b.ReportAllocs()
+	tower := entities.NewBasicTower(0, 0)
+	enemy := entities.NewEnemy(100, 10, 1, 1, benchPath)
+	for i := 0; i < b.N; i++ {
+		shotSink = entities.NewProjectile(tower, enemy, 10)
+	}
+}
+
+func BenchmarkProjectilePool(b *testing.B) {
+	b.ReportAllocs()
+	tower := entities.NewBasicTower(0, 0)
+	enemy := entities.NewEnemy(100, 10, 1, 1, benchPath)
+	pool := utils.NewPool[entities.Projectile]()
+	for i := 0; i < b.N; i++ {
+		shotSink = pool.Get()
+		shotSink.Launch(tower, enemy, 10)
+		pool.Put(shotSink)
+	}
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+	"time"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func TestClock(t *testing.T) {
+	clock := core.NewClock(time.Second / 10)
+	if clock.Tick() != 0 || clock.Elapsed() != 0 {
+		t.Errorf("Expected new clock at tick 0, got tick %d elapsed %v", clock.Tick(), clock.Elapsed())
+	}
+
+	for i := 0; i < 15; i++ {
+		clock.Advance()
+	}
+	if clock.Tick() != 15 {
+		t.Errorf("Expected tick 15, got %d", clock.Tick())
+	}
+	if clock.Elapsed() != 1500*time.Millisecond {
+		t.Errorf("Expected 1.5s elapsed, got %v", clock.Elapsed())
+	}
+
+	if core.NewClock(0).Delta() != core.DefaultTickDelta {
+		t.Error("Expected non-positive delta to fall back to DefaultTickDelta")
+	}
+}
+
+func TestUpdateAdvancesClock(t *testing.T) {
+	gs := core.NewGameState(core.WithClock(core.NewClock(time.Second / 10)))
+	gs.AddTower(core.BasicTower, 100, 100)
+
+	for i := 0; i < 5; i++ {
+		gs.Update()
+	}
+	if gs.GetTick() != 5 {
+		t.Errorf("Expected tick 5, got %d", gs.GetTick())
+	}
+	if gs.GetElapsed() != 500*time.Millisecond {
+		t.Errorf("Expected 500ms elapsed, got %v", gs.GetElapsed())
+	}
+
+	gs.SetPaused(true)
+	gs.Update()
+	if gs.GetTick() != 5 {
+		t.Error("Clock should not advance while paused")
+	}
+}
+
+func TestTowerCooldownFollowsTicks(t *testing.T) {
+	registry, err := core.NewTowerRegistry([]core.TowerDefinition{
+		{ID: "zap", Cost: 10, Range: 100, Damage: 10, FireRate: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(
+		core.WithClock(core.NewClock(time.Second/4)),
+		core.WithTowerRegistry(registry),
+	)
+	path := gs.GetEnemyPath()
+	if err := gs.AddTower("zap", path[0].X+10, path[0].Y-30); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs.AddEnemy(entities.NewEnemy(1000, 10, 1, 0, path))
+
+	// The tower fires once per second; at 4 ticks per second it should hit
+	// on ticks 1, 5 and 9.
+	for i := 0; i < 9; i++ {
+		gs.Update()
+	}
+	if health := gs.GetEnemies()[0].Health; health != 970 {
+		t.Errorf("Expected enemy health 970 after 9 ticks, got %d", health)
+	}
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func TestCommandsApplyOnUpdate(t *testing.T) {
+	gs := core.NewGameState()
+	built := gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 100, Y: 100})
+	bad := gs.Submit(core.BuildCommand{Tower: "unknown", X: 150, Y: 150})
+	targeting := gs.Submit(core.SetTargetingCommand{Tower: 1, Strategy: "weakest"})
+
+	if len(gs.GetTowers()) != 0 || gs.PendingCommands() != 3 {
+		t.Fatal("Commands should wait for the next Update")
+	}
+	gs.Update()
+	if gs.PendingCommands() != 0 {
+		t.Errorf("Expected the queue to be drained, %d left", gs.PendingCommands())
+	}
+
+	if result := <-built; result.Err != nil || result.Tick != 0 {
+		t.Errorf("Expected build to succeed at tick 0, got %+v", result)
+	}
+	if result := <-bad; result.Err == nil {
+		t.Error("Expected an error building an unknown tower")
+	}
+	if result := <-targeting; result.Err != nil {
+		t.Errorf("Expected targeting to apply to the new tower, got %v", result.Err)
+	}
+	if len(gs.GetTowers()) != 1 || gs.GetTowers()[0].Targeting.Name() != "weakest" {
+		t.Error("Expected one tower targeting the weakest enemy")
+	}
+
+	upgrade := gs.Submit(core.UpgradeCommand{Tower: 1})
+	sell := gs.Submit(core.SellCommand{Tower: 1})
+	gs.Update()
+	if result := <-upgrade; result.Err != nil || result.Tick != 1 {
+		t.Errorf("Expected upgrade to succeed at tick 1, got %+v", result)
+	}
+	if result := <-sell; result.Err != nil {
+		t.Errorf("Expected sell to succeed, got %v", result.Err)
+	}
+	if len(gs.GetTowers()) != 0 {
+		t.Error("Expected the tower to be sold")
+	}
+}
+
+func TestPauseAndCallWaveCommands(t *testing.T) {
+	gs := core.NewGameState()
+	gs.Submit(core.PauseCommand{})
+	gs.Update()
+	if !gs.IsPaused() || gs.GetTick() != 0 {
+		t.Fatal("Expected the game to pause before the tick ran")
+	}
+
+	// Commands still apply while paused, so the game can be resumed.
+	gs.Submit(core.CallWaveCommand{})
+	gs.Submit(core.PauseCommand{})
+	gs.Update()
+	if gs.IsPaused() {
+		t.Error("Expected the game to resume")
+	}
+	if gs.GetWave() != 1 {
+		t.Errorf("Expected wave 1 to be called, got %d", gs.GetWave())
+	}
+}
+
+func TestCommandsFollowTowersThroughSales(t *testing.T) {
+	gs := core.NewGameState()
+	var built []core.TowerBuilt
+	core.On(gs.Events(), func(e core.TowerBuilt) { built = append(built, e) })
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 100, Y: 100})
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 300, Y: 200})
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 500, Y: 200})
+	gs.Update()
+	if len(built) != 3 {
+		t.Fatalf("Expected 3 towers built, got %d", len(built))
+	}
+
+	// Selling the first tower moves the others around in GetTowers; the
+	// upgrade in the same tick must still reach the tower it named.
+	sell := gs.Submit(core.SellCommand{Tower: built[0].TowerID})
+	upgrade := gs.Submit(core.UpgradeCommand{Tower: built[1].TowerID})
+	gs.Update()
+	if r := <-sell; r.Err != nil {
+		t.Fatalf("Unexpected sell error: %v", r.Err)
+	}
+	if r := <-upgrade; r.Err != nil {
+		t.Fatalf("Unexpected upgrade error: %v", r.Err)
+	}
+	for _, tower := range gs.Snapshot().Towers {
+		want := 1
+		if tower.ID == built[1].TowerID {
+			want = 2
+		}
+		if tower.Level != want {
+			t.Errorf("Tower %d at (%.0f,%.0f): expected level %d, got %d", tower.ID, tower.X, tower.Y, want, tower.Level)
+		}
+	}
+
+	again := gs.Submit(core.SellCommand{Tower: built[0].TowerID})
+	gs.Update()
+	if r := <-again; r.Err == nil {
+		t.Error("Expected an error selling a tower that is gone")
+	}
+}
+
+func TestSetTowersKeepsIDsUnique(t *testing.T) {
+	gs := core.NewGameState()
+	placed := entities.NewBasicTower(100, 100)
+	placed.ID = 7
+	gs.SetTowers([]*entities.Tower{entities.NewBasicTower(300, 200), placed})
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 500, Y: 200})
+	gs.Update()
+
+	seen := make(map[uint64]bool)
+	for _, tower := range gs.Snapshot().Towers {
+		if seen[tower.ID] || tower.ID == 0 {
+			t.Errorf("Tower at (%.0f,%.0f) has a missing or duplicate ID %d", tower.X, tower.Y, tower.ID)
+		}
+		seen[tower.ID] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected 3 towers, got %d", len(seen))
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func TestShippedConfigMatchesDefaults(t *testing.T) {
+	cfg, err := core.LoadConfig("../../../configs/game_config.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %v", err)
+	}
+	registry, err := core.NewTowerRegistry(cfg.Towers)
+	if err != nil {
+		t.Fatalf("Unexpected error building registry: %v", err)
+	}
+
+	builtins := map[core.TowerType]*entities.Tower{
+		core.BasicTower:  entities.NewBasicTower(0, 0),
+		core.SniperTower: entities.NewSniperTower(0, 0),
+		core.AOETower:    entities.NewAOETower(0, 0),
+		core.FrostTower:  entities.NewFrostTower(0, 0),
+		core.PoisonTower: entities.NewPoisonTower(0, 0),
+	}
+	for id, want := range builtins {
+		got, ok := registry.Get(id)
+		if !ok {
+			t.Errorf("Expected tower %q in config", id)
+			continue
+		}
+		if got.Cost != want.Cost || got.Range != want.Range || got.Damage != want.Damage || got.FireRate != want.FireRate {
+			t.Errorf("Tower %q: config %+v does not match built-in %+v", id, got, want)
+		}
+		if (got.Effect == nil) != (want.Effect == nil) {
+			t.Errorf("Tower %q: config and built-in disagree on having an effect", id)
+		} else if got.Effect != nil && (got.Effect.Kind != want.Effect.Kind || got.Effect.Duration != want.Effect.Duration) {
+			t.Errorf("Tower %q: config effect %+v does not match built-in %+v", id, *got.Effect, *want.Effect)
+		}
+	}
+}
+
+func TestDefaultRegistryHasBuiltinTowers(t *testing.T) {
+	registry := core.DefaultTowerRegistry()
+	for _, id := range []core.TowerType{core.BasicTower, core.SniperTower, core.AOETower, core.FrostTower, core.PoisonTower} {
+		if _, ok := registry.Get(id); !ok {
+			t.Errorf("Expected built-in tower %q in the default registry", id)
+		}
+	}
+
+	gs := core.NewGameState()
+	if err := gs.AddTower(core.FrostTower, 300, 200); err != nil {
+		t.Errorf("Unexpected error building a frost tower: %v", err)
+	}
+	if err := gs.AddTower(core.PoisonTower, 225, 275); err != nil {
+		t.Errorf("Unexpected error building a poison tower: %v", err)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := core.ParseConfig([]byte(`
+towers:
+  - id: laser
+    name: Laser
+    cost: 75
+    range: 120
+    damage: 4
+    fire_rate: 250ms
+    targeting: closest
+    upgrades:
+      - {cost: 40, damage: 2, range: 10, fire_rate_multiplier: 0.8}
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	registry, err := core.NewTowerRegistry(cfg.Towers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	def, ok := registry.Get("laser")
+	if !ok {
+		t.Fatal("Expected laser tower in registry")
+	}
+	if def.FireRate != 250*time.Millisecond {
+		t.Errorf("Expected fire rate 250ms, got %v", def.FireRate)
+	}
+
+	tower := def.Build(10, 20)
+	if tower.X != 10 || tower.Y != 20 || tower.Type != "Laser" || tower.Targeting.Name() != "closest" {
+		t.Errorf("Unexpected tower built from definition: %+v", tower)
+	}
+	if tower.MaxLevel() != 2 || tower.GetUpgradeCost() != 40 {
+		t.Errorf("Expected 2 levels with upgrade cost 40, got %d levels costing %d", tower.MaxLevel(), tower.GetUpgradeCost())
+	}
+	if err := tower.Upgrade(); err != nil {
+		t.Fatalf("Unexpected upgrade error: %v", err)
+	}
+	if tower.Damage != 6 || tower.Range != 130 || tower.FireRate != 200*time.Millisecond {
+		t.Errorf("Unexpected stats after upgrade: damage %d range %f fire rate %v", tower.Damage, tower.Range, tower.FireRate)
+	}
+	if tower.Upgrade() == nil {
+		t.Error("Expected error upgrading past the last defined level")
+	}
+}
+
+func TestParseConfigRejectsUnknownKeys(t *testing.T) {
+	_, err := core.ParseConfig([]byte(`
+towers:
+  - id: laser
+    cost: 75
+    range: 120
+    fire_rate: 250ms
+    crit_chanse: 0.5
+`))
+	if err == nil || !strings.Contains(err.Error(), "crit_chanse") {
+		t.Errorf("Expected an error naming the misspelt key, got %v", err)
+	}
+}
+
+func TestTowerRegistryValidation(t *testing.T) {
+	valid := core.TowerDefinition{ID: "ok", Cost: 10, Range: 10, Damage: 1, FireRate: time.Second}
+
+	tests := []struct {
+		name   string
+		mutate func(*core.TowerDefinition)
+		errMsg string
+	}{
+		{"missing id", func(d *core.TowerDefinition) { d.ID = "" }, "missing id"},
+		{"zero cost", func(d *core.TowerDefinition) { d.Cost = 0 }, "cost"},
+		{"zero fire rate", func(d *core.TowerDefinition) { d.FireRate = 0 }, "fire_rate"},
+		{"bad targeting", func(d *core.TowerDefinition) { d.Targeting = "random" }, "targeting"},
+		{"bad special", func(d *core.TowerDefinition) { d.Special = "laser" }, "special"},
+		{"bad effect", func(d *core.TowerDefinition) {
+			d.Effect = &core.EffectDefinition{Kind: "freeze", Duration: time.Second}
+		}, "effect: unknown kind"},
+		{"crit without multiplier", func(d *core.TowerDefinition) { d.CritChance = 1 }, "crit_multiplier"},
+		{"effect without duration", func(d *core.TowerDefinition) { d.Effect = &core.EffectDefinition{Kind: "slow"} }, "duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := valid
+			tt.mutate(&def)
+			_, err := core.NewTowerRegistry([]core.TowerDefinition{valid, def})
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error mentioning %q, got %v", tt.errMsg, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "tower 2") {
+				t.Errorf("Expected error to point at tower 2, got %v", err)
+			}
+		})
+	}
+
+	if _, err := core.NewTowerRegistry([]core.TowerDefinition{valid, valid}); err == nil {
+		t.Error("Expected duplicate id error")
+	}
+}
+
+func TestAddTowerFromRegistry(t *testing.T) {
+	registry, err := core.NewTowerRegistry([]core.TowerDefinition{
+		{ID: "wall", Name: "Wall", Cost: 5, Range: 1, FireRate: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithTowerRegistry(registry))
+
+	if err := gs.AddTower("wall", 50, 50); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gs.GetMoney() != 995 {
+		t.Errorf("Expected wall to cost 5, money is %d", gs.GetMoney())
+	}
+	if err := gs.AddTower(core.BasicTower, 60, 60); err == nil {
+		t.Error("Expected error for tower missing from registry")
+	}
+	if defs := gs.GetTowerDefinitions(); len(defs) != 1 || defs[0].Name != "Wall" {
+		t.Errorf("Expected one Wall definition, got %+v", defs)
+	}
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func TestEventBusSubscribeUnsubscribe(t *testing.T) {
+	bus := core.NewEventBus()
+	var kills, all int
+	id := core.On(bus, func(e core.EnemyKilled) { kills += e.Reward })
+	bus.Subscribe("", func(core.Event) { all++ })
+
+	bus.Publish(core.EnemyKilled{Reward: 5})
+	bus.Publish(core.WaveStarted{Wave: 1})
+	if kills != 5 || all != 2 {
+		t.Errorf("Expected kills 5 and 2 events, got %d and %d", kills, all)
+	}
+
+	bus.Unsubscribe(id)
+	bus.Publish(core.EnemyKilled{Reward: 5})
+	if kills != 5 || all != 3 {
+		t.Errorf("Expected unsubscribed handler to stay quiet, got kills %d and %d events", kills, all)
+	}
+}
+
+func TestGameStatePublishesEvents(t *testing.T) {
+	bus := core.NewEventBus()
+	var kinds []core.EventKind
+	bus.Subscribe("", func(e core.Event) { kinds = append(kinds, e.Kind()) })
+	gs := core.NewGameState(core.WithEventBus(bus))
+
+	// Handlers run outside the lock and may read the state.
+	var money int
+	core.On(bus, func(core.TowerBuilt) { money = gs.GetMoney() })
+
+	if err := gs.AddTower(core.BasicTower, 100, 100); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if money != 950 {
+		t.Errorf("Expected handler to see money 950, got %d", money)
+	}
+	gs.UpgradeTower(0)
+	gs.SellTower(0)
+	gs.AddEnemy(entities.NewEnemy(10, 7, 1, 1, gs.GetEnemyPath()))
+	gs.DamageEnemy(0, 10)
+	gs.NextWave()
+
+	want := []core.EventKind{
+		core.EventTowerBuilt, core.EventTowerUpgraded, core.EventTowerSold,
+		core.EventEnemyKilled, core.EventWaveStarted,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, want[i], kinds[i])
+		}
+	}
+}
+
+func TestLeakAndGameOverEvents(t *testing.T) {
+	gs := core.NewGameState()
+	var leaked []core.EnemyLeaked
+	var over int
+	core.On(gs.Events(), func(e core.EnemyLeaked) { leaked = append(leaked, e) })
+	core.On(gs.Events(), func(core.GameOver) { over++ })
+
+	gs.SetLives(3)
+	path := []entities.BaseEntity{{X: 0, Y: 300}, {X: 1, Y: 300}}
+	gs.AddEnemy(entities.NewEnemy(100, 10, 2, 5, path))
+	gs.AddEnemy(entities.NewEnemy(100, 10, 2, 5, path))
+	gs.Update()
+
+	if len(leaked) != 2 || leaked[0].Lives != 1 || leaked[1].Lives != 0 {
+		t.Errorf("Expected two leaks leaving 1 then 0 lives, got %+v", leaked)
+	}
+	if over != 1 {
+		t.Errorf("Expected one GameOver event, got %d", over)
+	}
+	gs.LoseLife(1)
+	if over != 1 {
+		t.Error("GameOver should only be published once")
+	}
+}
@@ -1,9 +1,12 @@
 package core
 
 import (
+	"reflect"
 	"testing"
+	"time"
 	"tower-defense/internal/core"
 	"tower-defense/internal/entities"
+	"tower-defense/internal/systems"
 )
 
 func TestNewGameState(t *testing.T) {
@@ -24,8 +27,8 @@ func TestNewGameState(t *testing.T) {
 	if gs.GetWave() != 0 {
 		t.Errorf("Expected wave 0, got %d", gs.GetWave())
 	}
-	if len(gs.GetTowerCosts()) != 3 {
-		t.Errorf("Expected 3 tower types, got %d", len(gs.GetTowerCosts()))
+	if len(gs.GetTowerCosts()) != 5 {
+		t.Errorf("Expected 5 tower types, got %d", len(gs.GetTowerCosts()))
 	}
 	if gs.IsPaused() {
 		t.Error("Expected game to start unpaused")
@@ -45,9 +48,9 @@ func TestAddTower(t *testing.T) {
 		expectErr bool
 	}{
 		{"Add Basic Tower", core.BasicTower, 100, 100, false},
-		{"Add Sniper Tower", core.SniperTower, 200, 200, false},
+		{"Add Sniper Tower", core.SniperTower, 250, 200, false},
 		{"Add AOE Tower", core.AOETower, 300, 300, false},
-		{"Invalid Tower Type", core.TowerType(999), 400, 400, true},
+		{"Invalid Tower Type", core.TowerType("unknown"), 400, 400, true},
 		{"Not Enough Money", core.BasicTower, 500, 500, true},
 	}
 
@@ -278,8 +281,8 @@ func TestGetterMethods(t *testing.T) {
 	if gs.GetWave() != 0 {
 		t.Error("GetWave should return 0")
 	}
-	if len(gs.GetTowerCosts()) != 3 {
-		t.Error("GetTowerCosts should return 3 tower types")
+	if len(gs.GetTowerCosts()) != 5 {
+		t.Error("GetTowerCosts should return 5 tower types")
 	}
 	if gs.IsPaused() {
 		t.Error("IsPaused should return false initially")
@@ -288,3 +291,204 @@ func TestGetterMethods(t *testing.T) {
 		t.Error("GetEnemyPath should return 8 path points")
 	}
 }
+
+func TestSeededGamesAreReproducible(t *testing.T) {
+	play := func(seed int64) []float64 {
+		gs := core.NewGameState(core.WithSeed(seed))
+		gs.AddTower(core.SniperTower, 225, 250)
+		gs.NextWave()
+		gs.NextWave()
+		for i := 0; i < 300; i++ {
+			gs.Update()
+		}
+		var trace []float64
+		for _, e := range gs.GetEnemies() {
+			trace = append(trace, e.Speed, e.X, e.Y, float64(e.Health))
+		}
+		return trace
+	}
+
+	a, b := play(42), play(42)
+	if len(a) != len(b) {
+		t.Fatalf("Expected identical runs, got %d and %d values", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Runs with the same seed diverged at value %d: %f != %f", i, a[i], b[i])
+		}
+	}
+
+	gs := core.NewGameState(core.WithSeed(42))
+	if gs.GetSeed() != 42 {
+		t.Errorf("Expected seed 42 to be recorded, got %d", gs.GetSeed())
+	}
+	if core.NewGameState().GetSeed() != core.DefaultSeed {
+		t.Error("Expected games without WithSeed to use DefaultSeed")
+	}
+}
+
+func TestSetTowerTargeting(t *testing.T) {
+	gs := core.NewGameState()
+	gs.AddTower(core.BasicTower, 100, 100)
+
+	if err := gs.SetTowerTargeting(0, "strongest"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if name := gs.GetTowers()[0].Targeting.Name(); name != "strongest" {
+		t.Errorf("Expected strongest targeting, got %s", name)
+	}
+	if err := gs.SetTowerTargeting(0, "bogus"); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+	if err := gs.SetTowerTargeting(5, "first"); err == nil {
+		t.Error("Expected error for invalid tower index")
+	}
+}
+
+func TestEnemiesByProgress(t *testing.T) {
+	gs := core.NewGameState()
+	if gs.LeadingEnemy() != nil {
+		t.Error("Expected no leading enemy without enemies")
+	}
+
+	path := gs.GetEnemyPath()
+	slow := entities.NewEnemy(100, 10, 1, 1.0, path)
+	fast := entities.NewEnemy(100, 10, 1, 5.0, path)
+	medium := entities.NewEnemy(100, 10, 1, 3.0, path)
+	gs.AddEnemy(slow)
+	gs.AddEnemy(fast)
+	gs.AddEnemy(medium)
+	for i := 0; i < 10; i++ {
+		gs.Update()
+	}
+
+	ordered := gs.EnemiesByProgress()
+	if len(ordered) != 3 || ordered[0].ID != fast.ID || ordered[1].ID != medium.ID || ordered[2].ID != slow.ID {
+		t.Error("Expected enemies ordered fast, medium, slow")
+	}
+	if leader := gs.LeadingEnemy(); leader == nil || leader.ID != fast.ID {
+		t.Error("Expected the fast enemy to be closest to the exit")
+	}
+
+	// Views stay as they were when taken, even once the enemy is recycled.
+	kept := ordered[0]
+	gs.RemoveEnemy(0)
+	gs.NextWave()
+	if kept.ID != fast.ID || kept.Health != 100 {
+		t.Errorf("Expected the kept view to be unchanged, got %+v", kept)
+	}
+}
+
+func TestEnemiesAreRecycled(t *testing.T) {
+	gs := core.NewGameState()
+	gs.NextWave()
+	enemy := gs.GetEnemies()[0]
+	firstID := enemy.ID
+	enemy.ApplyEffect(entities.StatusEffect{Kind: entities.EffectSlow, Duration: time.Minute, SpeedMultiplier: 0.5})
+	enemy.PathIndex = len(enemy.Path) - 1 // at the exit
+
+	gs.Update()
+	for _, e := range gs.GetEnemies() {
+		if e == enemy {
+			t.Fatal("Expected the enemy at the exit to be removed")
+		}
+	}
+
+	// Keep ticking until the next enemy comes out of the pool.
+	for i := 0; i < 600; i++ {
+		gs.Update()
+		for _, e := range gs.GetEnemies() {
+			if e != enemy {
+				continue
+			}
+			if e.ID == firstID {
+				t.Error("Expected a recycled enemy to get a new ID")
+			}
+			if len(e.Effects) != 0 || e.PathIndex != 0 {
+				t.Errorf("Expected a recycled enemy to start fresh, got %d effects at path index %d", len(e.Effects), e.PathIndex)
+			}
+			return
+		}
+	}
+	t.Error("Expected a later spawn to reuse the removed enemy")
+}
+
+type tickCounter struct{ ticks *int }
+
+func (tickCounter) Name() string { return "counter" }
+
+func (c tickCounter) Update(w *systems.World) { *c.ticks++ }
+
+func TestUpdatePipeline(t *testing.T) {
+	gs := core.NewGameState()
+	want := []string{
+		core.SpawningSystem, core.TargetingSystem, core.CollisionSystem,
+		core.MovementSystem, core.DeathSystem, core.CleanupSystem, core.WaveSystem,
+	}
+	if got := gs.GetSystems(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected systems %v, got %v", want, got)
+	}
+
+	ticks := 0
+	if err := gs.AddSystem(tickCounter{&ticks}, core.WaveSystem); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := gs.AddSystem(tickCounter{&ticks}, "missing"); err == nil {
+		t.Error("Expected error inserting before an unknown system")
+	}
+
+	enemy := entities.NewEnemy(100, 10, 1, 5, gs.GetEnemyPath())
+	gs.AddEnemy(enemy)
+	if err := gs.SetSystemEnabled(core.MovementSystem, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs.Update()
+	if enemy.Progress() != 0 {
+		t.Error("Enemy should not move with the movement system disabled")
+	}
+	if ticks != 1 {
+		t.Errorf("Expected custom system to run once, ran %d times", ticks)
+	}
+
+	gs.SetSystemEnabled(core.MovementSystem, true)
+	gs.Update()
+	if enemy.Progress() == 0 {
+		t.Error("Enemy should move once the movement system is enabled again")
+	}
+}
+
+func TestTowerKillsArePaidDuringUpdate(t *testing.T) {
+	registry, err := core.NewTowerRegistry([]core.TowerDefinition{
+		{ID: "zap", Name: "Zap", Cost: 10, Range: 100, Damage: 50, FireRate: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithTowerRegistry(registry))
+	path := gs.GetEnemyPath()
+	if err := gs.AddTower("zap", path[0].X+10, path[0].Y-30); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var killed []core.EnemyKilled
+	core.On(gs.Events(), func(e core.EnemyKilled) { killed = append(killed, e) })
+	gs.AddEnemy(entities.NewEnemy(40, 25, 1, 0, path))
+	gs.AddEnemy(entities.NewEnemy(1000, 25, 1, 0, path))
+	money := gs.GetMoney()
+
+	gs.Update()
+	if len(gs.GetEnemies()) != 1 {
+		t.Fatalf("Expected the dead enemy to be removed, %d left", len(gs.GetEnemies()))
+	}
+	if gs.GetMoney() != money+25 {
+		t.Errorf("Expected reward of 25, money went from %d to %d", money, gs.GetMoney())
+	}
+	if gs.GetScore() != 40 {
+		t.Errorf("Expected score 40, got %d", gs.GetScore())
+	}
+	if kills := gs.GetTowers()[0].Kills; kills != 1 {
+		t.Errorf("Expected the tower to be credited with 1 kill, got %d", kills)
+	}
+	if len(killed) != 1 || killed[0].TowerID != gs.GetTowers()[0].ID || killed[0].TowerType != "zap" {
+		t.Errorf("Expected one kill by the zap tower, got %+v", killed)
+	}
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func TestGridTiles(t *testing.T) {
+	grid := core.NewGrid(4, 3, 10)
+
+	if col, row := grid.TileAt(25, 19.9); col != 2 || row != 1 {
+		t.Errorf("Expected tile (2,1), got (%d,%d)", col, row)
+	}
+	if x, y := grid.Snap(21, 18); x != 25 || y != 15 {
+		t.Errorf("Expected snap to (25,15), got (%f,%f)", x, y)
+	}
+	if grid.At(1, 1) != core.TerrainBuildable {
+		t.Error("Expected new tiles to be buildable")
+	}
+	grid.Set(1, 1, core.TerrainWater)
+	if grid.At(1, 1) != core.TerrainWater {
+		t.Error("Expected tile to become water")
+	}
+	if grid.At(-1, 0) != core.TerrainBlocked || grid.At(4, 0) != core.TerrainBlocked {
+		t.Error("Expected tiles outside the grid to be blocked")
+	}
+}
+
+func TestGridMarkPath(t *testing.T) {
+	grid := core.NewGrid(5, 5, 10)
+	grid.MarkPath([]entities.BaseEntity{{X: 0, Y: 5}, {X: 25, Y: 5}, {X: 25, Y: 50}})
+
+	for _, tile := range [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 4}} {
+		if grid.At(tile[0], tile[1]) != core.TerrainPath {
+			t.Errorf("Expected tile %v to be path", tile)
+		}
+	}
+	if grid.At(3, 0) != core.TerrainBuildable || grid.At(1, 1) != core.TerrainBuildable {
+		t.Error("Expected tiles off the path to stay buildable")
+	}
+
+	grid.ClearPath()
+	if grid.At(2, 4) != core.TerrainBuildable {
+		t.Error("Expected ClearPath to reset path tiles")
+	}
+}
+
+func TestAddTowerPlacementErrors(t *testing.T) {
+	grid := core.NewGrid(10, 10, 20)
+	grid.MarkPath([]entities.BaseEntity{{X: 0, Y: 10}, {X: 200, Y: 10}})
+	grid.Set(5, 5, core.TerrainWater)
+	grid.Set(6, 6, core.TerrainBlocked)
+	gs := core.NewGameState(core.WithGrid(grid))
+
+	if err := gs.AddTower(core.BasicTower, 43, 47); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if x, y := gs.GetTowers()[0].GetPosition(); x != 50 || y != 50 {
+		t.Errorf("Expected tower snapped to (50,50), got (%f,%f)", x, y)
+	}
+
+	tests := []struct {
+		name   string
+		x, y   float64
+		reason error
+	}{
+		{"occupied", 55, 55, core.ErrOccupied},
+		{"on path", 100, 5, core.ErrOnPath},
+		{"water", 110, 110, core.ErrNotBuildable},
+		{"blocked", 130, 130, core.ErrNotBuildable},
+		{"out of bounds", 250, 50, core.ErrOutOfBounds},
+		{"negative", -5, 50, core.ErrOutOfBounds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money := gs.GetMoney()
+			err := gs.AddTower(core.BasicTower, tt.x, tt.y)
+			if !errors.Is(err, tt.reason) {
+				t.Errorf("Expected %v, got %v", tt.reason, err)
+			}
+			var placement *core.PlacementError
+			if !errors.As(err, &placement) {
+				t.Errorf("Expected a PlacementError, got %T", err)
+			}
+			if gs.GetMoney() != money || len(gs.GetTowers()) != 1 {
+				t.Error("Failed placement should not cost money or add a tower")
+			}
+		})
+	}
+
+	gs.SellTower(0)
+	if err := gs.AddTower(core.BasicTower, 55, 55); err != nil {
+		t.Errorf("Expected tile to be free after selling, got %v", err)
+	}
+}
+
+func TestDefaultGridFollowsPath(t *testing.T) {
+	gs := core.NewGameState()
+	grid := gs.GetGrid()
+	for _, point := range gs.GetEnemyPath()[:len(gs.GetEnemyPath())-1] {
+		col, row := grid.TileAt(point.X, point.Y)
+		if grid.At(col, row) != core.TerrainPath {
+			t.Errorf("Expected path point (%f,%f) to be on a path tile", point.X, point.Y)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"tower-defense/internal/core"
+)
+
+func TestShippedMapsLoad(t *testing.T) {
+	for _, name := range []string{"classic", "lakeside", "crossroads", "open_field"} {
+		t.Run(name, func(t *testing.T) {
+			level, err := core.LoadLevel("../../../assets/maps/" + name + ".json")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			waves, err := core.LoadWaves(level.WavesFile)
+			if err == nil {
+				err = waves.ValidateSpawns(len(level.Spawns))
+			}
+			if err != nil {
+				t.Errorf("Expected map's wave file %s to load, got %v", level.WavesFile, err)
+			}
+			if w, h := level.Grid.WorldSize(); w != 800 || h != 600 {
+				t.Errorf("Expected an 800x600 world, got %fx%f", w, h)
+			}
+		})
+	}
+}
+
+const testMap = `{
+	"name": "Test",
+	"tile_size": 10,
+	"tiles": [
+		".....",
+		"..~..",
+		"....."
+	],
+	"spawns": [[0, 0]],
+	"exits": [[4, 2]],
+	"path": [[0, 0], [4, 0], [4, 2]],
+	"decorations": [{"tile": [1, 2], "glyph": "*"}],
+	"money": 300,
+	"lives": 5,
+	"waves": "waves.json"
+}`
+
+func TestParseLevel(t *testing.T) {
+	level, err := core.ParseLevel([]byte(testMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if level.Name != "Test" || level.Money != 300 || level.Lives != 5 || level.WavesFile != "waves.json" {
+		t.Errorf("Unexpected level metadata %+v", level)
+	}
+	path := level.Paths.Route(0, nil)
+	if len(path) != 3 || path[0].X != 5 || path[0].Y != 5 || path[2].X != 45 || path[2].Y != 25 {
+		t.Errorf("Expected path through tile centres, got %v", path)
+	}
+	if level.Grid.At(2, 1) != core.TerrainWater || level.Grid.At(2, 0) != core.TerrainPath || level.Grid.At(2, 2) != core.TerrainBuildable {
+		t.Error("Expected grid to hold water, path and buildable tiles")
+	}
+
+	gs := core.NewGameState(core.WithLevel(level))
+	if gs.GetMoney() != 300 || gs.GetLives() != 5 || gs.GetLevelName() != "Test" {
+		t.Error("Expected game to start with the level's resources")
+	}
+	if len(gs.GetDecorations()) != 1 {
+		t.Error("Expected the level's decoration")
+	}
+	if err := gs.AddTower(core.BasicTower, 25, 15); !errors.Is(err, core.ErrNotBuildable) {
+		t.Errorf("Expected water to be unbuildable, got %v", err)
+	}
+	if err := gs.AddTower(core.BasicTower, 15, 5); !errors.Is(err, core.ErrOnPath) {
+		t.Errorf("Expected path tile to be unbuildable, got %v", err)
+	}
+}
+
+func TestLevelValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace [2]string
+		errMsg  string
+	}{
+		{"ragged rows", [2]string{`"..~.."`, `"..~."`}, "tiles row 2: expected 5 columns"},
+		{"unknown tile", [2]string{`"..~.."`, `"..?.."`}, "unknown tile"},
+		{"path through water", [2]string{`[[0, 0], [4, 0], [4, 2]]`, `[[0, 0], [0, 1], [4, 1], [4, 2]]`}, "crosses water"},
+		{"diagonal path", [2]string{`[[0, 0], [4, 0], [4, 2]]`, `[[0, 0], [4, 2]]`}, "not horizontal or vertical"},
+		{"path not from spawn", [2]string{`"spawns": [[0, 0]]`, `"spawns": [[1, 0]]`}, "spawn 1: no path starts there"},
+		{"path not to exit", [2]string{`"exits": [[4, 2]]`, `"exits": [[4, 1]]`}, "must end at an exit"},
+		{"spawn out of bounds", [2]string{`"spawns": [[0, 0]]`, `"spawns": [[0, 0], [9, 9]]`}, "spawn 2"},
+		{"no lives", [2]string{`"lives": 5`, `"lives": 0`}, "lives"},
+		{"long glyph", [2]string{`"glyph": "*"`, `"glyph": "**"`}, "decoration 1"},
+		{"unknown field", [2]string{`"money"`, `"gold"`}, "gold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.Replace(testMap, tt.replace[0], tt.replace[1], 1)
+			if data == testMap {
+				t.Fatalf("Replacement %q did not apply", tt.replace[0])
+			}
+			_, err := core.ParseLevel([]byte(data))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
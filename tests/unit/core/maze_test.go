@@ -0,0 +1,171 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+// A 7x3 field with a spawn on the left and an exit on the right.
+const mazeMap = `{
+	"name": "Maze",
+	"mode": "maze",
+	"tile_size": 10,
+	"tiles": [
+		".......",
+		".......",
+		"......."
+	],
+	"spawns": [[0, 1]],
+	"exits": [[6, 1]],
+	"money": 1000,
+	"lives": 5
+}`
+
+func newMazeGame(t *testing.T) *core.GameState {
+	t.Helper()
+	level, err := core.ParseLevel([]byte(mazeMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !level.Maze {
+		t.Fatal("Expected a maze level")
+	}
+	return core.NewGameState(core.WithLevel(level))
+}
+
+func TestFindPath(t *testing.T) {
+	grid := core.NewGrid(5, 5, 10)
+	for row := 0; row < 4; row++ {
+		grid.Set(2, row, core.TerrainBlocked)
+	}
+	maze, err := core.NewMaze(grid, []core.TilePos{{0, 0}}, []core.TilePos{{4, 0}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	route := maze.FindPath(core.TilePos{0, 0})
+	// Down to the gap in the bottom row, across and back up: 4 + 4 + 4 steps.
+	if len(route) != 13 {
+		t.Fatalf("Expected a 13 tile route around the wall, got %v", route)
+	}
+	for i := 1; i < len(route); i++ {
+		dc, dr := route[i][0]-route[i-1][0], route[i][1]-route[i-1][1]
+		if dc*dc+dr*dr != 1 {
+			t.Errorf("Expected neighbouring tiles, got %v then %v", route[i-1], route[i])
+		}
+		if !maze.Walkable(route[i]) {
+			t.Errorf("Expected route to avoid blocked tile %v", route[i])
+		}
+	}
+
+	grid.Set(2, 4, core.TerrainWater)
+	if route := maze.FindPath(core.TilePos{0, 0}); route != nil {
+		t.Errorf("Expected no route once the gap is flooded, got %v", route)
+	}
+	if _, err := core.NewMaze(grid, []core.TilePos{{0, 0}}, []core.TilePos{{4, 0}}); err == nil {
+		t.Error("Expected a maze without a way through to be rejected")
+	}
+}
+
+func TestTowersBlockMazePaths(t *testing.T) {
+	gs := newMazeGame(t)
+	if got := len(gs.GetEnemyPath()); got != 7 {
+		t.Fatalf("Expected a straight 7 tile path, got %d", got)
+	}
+
+	if err := gs.AddTower(core.BasicTower, 35, 15); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	path := gs.GetEnemyPath()
+	if len(path) != 9 {
+		t.Errorf("Expected the path to step around the tower, got %v", path)
+	}
+	for _, point := range path {
+		if point.X == 35 && point.Y == 15 {
+			t.Error("Expected the path to avoid the tower's tile")
+		}
+	}
+
+	if err := gs.AddTower(core.BasicTower, 35, 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err := gs.AddTower(core.BasicTower, 35, 25)
+	if !errors.Is(err, core.ErrBlocksPath) {
+		t.Errorf("Expected sealing off the exit to fail with ErrBlocksPath, got %v", err)
+	}
+	if len(gs.GetTowers()) != 2 {
+		t.Errorf("Expected the blocking tower not to be built, got %d towers", len(gs.GetTowers()))
+	}
+
+	if err := gs.SellTower(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := len(gs.GetEnemyPath()); got != 7 {
+		t.Errorf("Expected the straight path back after selling, got %d points", got)
+	}
+}
+
+func TestEnemiesRerouteAroundNewTowers(t *testing.T) {
+	gs := newMazeGame(t)
+	enemy := entities.NewEnemy(100, 1, 1, 1, gs.GetEnemyPath())
+	gs.AddEnemy(enemy)
+	other := entities.NewEnemy(100, 1, 1, 1, gs.GetEnemyPath())
+	other.Reroute(other.Path[:2]) // not heading past column 4
+	gs.AddEnemy(other)
+
+	err := gs.AddTower(core.BasicTower, 5, 15)
+	if !errors.Is(err, core.ErrOccupied) {
+		t.Errorf("Expected building on an enemy to fail with ErrOccupied, got %v", err)
+	}
+
+	if err := gs.AddTower(core.BasicTower, 45, 15); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, point := range enemy.Path {
+		if point.X == 45 && point.Y == 15 {
+			t.Fatal("Expected the enemy to be rerouted around the new tower")
+		}
+	}
+	if enemy.PathIndex != 0 || enemy.Path[0].X != enemy.X || enemy.Path[0].Y != enemy.Y {
+		t.Error("Expected the new route to start where the enemy stands")
+	}
+	if enemy.PathLength() != 80 {
+		t.Errorf("Expected the detour to lengthen the path to 80, got %f", enemy.PathLength())
+	}
+	if len(other.Path) != 2 {
+		t.Error("Expected an enemy whose way was clear to keep its path")
+	}
+}
+
+func TestMazeLevelValidation(t *testing.T) {
+	data := strings.Replace(mazeMap, `"spawns"`, `"path": [[0, 1], [6, 1]], "spawns"`, 1)
+	if _, err := core.ParseLevel([]byte(data)); err == nil || !strings.Contains(err.Error(), "must not have a path") {
+		t.Errorf("Expected maze with a path to be rejected, got %v", err)
+	}
+	data = strings.Replace(mazeMap, `"maze"`, `"labyrinth"`, 1)
+	if _, err := core.ParseLevel([]byte(data)); err == nil || !strings.Contains(err.Error(), "unknown mode") {
+		t.Errorf("Expected unknown mode to be rejected, got %v", err)
+	}
+	data = strings.Replace(mazeMap, `"......."`, `"...#..."`, 3)
+	if _, err := core.ParseLevel([]byte(data)); err == nil || !strings.Contains(err.Error(), "no way through") {
+		t.Errorf("Expected walled-off exit to be rejected, got %v", err)
+	}
+}
+
+func TestWithLevelPanicsOnBrokenMaze(t *testing.T) {
+	level, err := core.ParseLevel([]byte(mazeMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A hand-built copy has no spawn or exit tiles to build a maze from.
+	broken := &core.Level{Name: level.Name, Grid: level.Grid, Paths: level.Paths, Maze: true}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected a maze level that cannot build its maze to panic")
+		}
+	}()
+	core.NewGameState(core.WithLevel(broken))
+}
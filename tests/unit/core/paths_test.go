@@ -0,0 +1,168 @@
+package core
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func pt(x, y float64) entities.BaseEntity {
+	return entities.BaseEntity{X: x, Y: y}
+}
+
+// forkedNetwork has two spawns that merge at (100,100), then a fork into a
+// north branch weighted 3 and a south branch weighted 1 that meet again at
+// (300,100) before the exit.
+func forkedNetwork(t *testing.T) *core.PathNetwork {
+	t.Helper()
+	network, err := core.NewPathNetwork(
+		[]entities.BaseEntity{pt(0, 0), pt(0, 200)},
+		[]core.PathSegment{
+			{Points: []entities.BaseEntity{pt(0, 0), pt(100, 0), pt(100, 100)}},
+			{Points: []entities.BaseEntity{pt(0, 200), pt(100, 200), pt(100, 100)}},
+			{Points: []entities.BaseEntity{pt(100, 100), pt(200, 50), pt(300, 100)}, Weight: 3},
+			{Points: []entities.BaseEntity{pt(100, 100), pt(200, 150), pt(300, 100)}, Weight: 1},
+			{Points: []entities.BaseEntity{pt(300, 100), pt(400, 100)}},
+		})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return network
+}
+
+func TestRouteFollowsMainBranch(t *testing.T) {
+	network := forkedNetwork(t)
+
+	route := network.Route(1, nil)
+	expected := []entities.BaseEntity{pt(0, 200), pt(100, 200), pt(100, 100), pt(200, 50), pt(300, 100), pt(400, 100)}
+	if len(route) != len(expected) {
+		t.Fatalf("Expected %d points, got %v", len(expected), route)
+	}
+	for i := range expected {
+		if route[i] != expected[i] {
+			t.Errorf("Expected point %d to be %v, got %v", i, expected[i], route[i])
+		}
+	}
+
+	if route := network.Route(7, nil); route[0] != pt(0, 0) {
+		t.Errorf("Expected unknown spawn to fall back to the first, got %v", route[0])
+	}
+}
+
+func TestRouteChoosesBranchesByWeight(t *testing.T) {
+	network := forkedNetwork(t)
+	rng := rand.New(rand.NewSource(1))
+
+	north := 0
+	const runs = 4000
+	for i := 0; i < runs; i++ {
+		route := network.Route(i%2, rng)
+		if route[len(route)-1] != pt(400, 100) {
+			t.Fatalf("Expected every route to reach the exit, got %v", route)
+		}
+		if route[3] == pt(200, 50) {
+			north++
+		}
+	}
+	if share := float64(north) / runs; share < 0.7 || share > 0.8 {
+		t.Errorf("Expected about 75%% of enemies to take the north branch, got %.2f", share)
+	}
+}
+
+func TestPathNetworkValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		spawns   []entities.BaseEntity
+		segments []core.PathSegment
+		errMsg   string
+	}{
+		{
+			name:     "spawn without path",
+			spawns:   []entities.BaseEntity{pt(0, 0), pt(0, 50)},
+			segments: []core.PathSegment{{Points: []entities.BaseEntity{pt(0, 0), pt(100, 0)}}},
+			errMsg:   "spawn 2",
+		},
+		{
+			name:   "dangling path",
+			spawns: []entities.BaseEntity{pt(0, 0)},
+			segments: []core.PathSegment{
+				{Points: []entities.BaseEntity{pt(0, 0), pt(100, 0)}},
+				{Points: []entities.BaseEntity{pt(50, 50), pt(100, 50)}},
+			},
+			errMsg: "path 2: does not start",
+		},
+		{
+			name:   "loop",
+			spawns: []entities.BaseEntity{pt(0, 0)},
+			segments: []core.PathSegment{
+				{Points: []entities.BaseEntity{pt(0, 0), pt(100, 0)}},
+				{Points: []entities.BaseEntity{pt(100, 0), pt(100, 100), pt(200, 0)}},
+				{Points: []entities.BaseEntity{pt(200, 0), pt(100, 0)}},
+			},
+			errMsg: "leads back to itself",
+		},
+		{
+			name:     "negative weight",
+			spawns:   []entities.BaseEntity{pt(0, 0)},
+			segments: []core.PathSegment{{Points: []entities.BaseEntity{pt(0, 0), pt(100, 0)}, Weight: -1}},
+			errMsg:   "weight",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := core.NewPathNetwork(tt.spawns, tt.segments)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestGroupsUseTheirSpawn(t *testing.T) {
+	waves, err := core.ParseWaves([]byte(`{
+		"enemies": {"grunt": {"health": 10, "speed": 1, "reward": 1, "damage": 1}},
+		"waves": [{"groups": [
+			{"enemy": "grunt", "count": 1, "spawn": 0},
+			{"enemy": "grunt", "count": 1, "spawn": 1}
+		]}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithPaths(forkedNetwork(t)), core.WithWaves(waves))
+	gs.NextWave()
+
+	enemies := gs.GetEnemies()
+	if len(enemies) != 2 {
+		t.Fatalf("Expected 2 enemies, got %d", len(enemies))
+	}
+	if enemies[0].Path[0] != pt(0, 0) || enemies[1].Path[0] != pt(0, 200) {
+		t.Errorf("Expected enemies to start at their group's spawn, got %v and %v", enemies[0].Path[0], enemies[1].Path[0])
+	}
+	if enemies[0].X != 0 || enemies[0].Y != 0 || enemies[1].X != 0 || enemies[1].Y != 200 {
+		t.Error("Expected enemies to be placed on their spawn")
+	}
+	for _, segment := range forkedNetwork(t).Segments {
+		col, row := gs.GetGrid().TileAt(segment.Points[0].X, segment.Points[0].Y)
+		if gs.GetGrid().At(col, row) != core.TerrainPath {
+			t.Errorf("Expected every branch to be marked on the grid, (%d,%d) is not", col, row)
+		}
+	}
+}
+
+func TestNoRouteSpawnsNothing(t *testing.T) {
+	for _, path := range [][]entities.BaseEntity{
+		{pt(0, 0)},
+		{pt(0, 0), pt(50, 0), pt(0, 0)},
+	} {
+		gs := core.NewGameState()
+		gs.SetEnemyPath(path)
+		gs.NextWave()
+		gs.Update()
+		if n := len(gs.GetEnemies()); n != 0 {
+			t.Errorf("Path %v: expected no enemies without a route, got %d", path, n)
+		}
+	}
+}
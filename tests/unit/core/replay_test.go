@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"tower-defense/internal/core"
+)
+
+func recordGame(t *testing.T) *core.Replay {
+	t.Helper()
+	recorder := core.NewRecorder(core.Replay{Seed: 7})
+	gs := core.NewGameState(core.WithSeed(7), core.WithRecorder(recorder))
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 225, Y: 275})
+	gs.Submit(core.CallWaveCommand{})
+	for tick := 0; tick < 300; tick++ {
+		switch tick {
+		case 50:
+			gs.Submit(core.SetTargetingCommand{Tower: 1, Strategy: "strongest"})
+		case 100:
+			gs.Submit(core.PauseCommand{})
+		case 103:
+			gs.Submit(core.UpgradeCommand{Tower: 1})
+			gs.Submit(core.SellCommand{Tower: 5}) // fails
+			gs.Submit(core.PauseCommand{})
+		case 200:
+			gs.Submit(core.BuildCommand{Tower: core.SniperTower, X: 300, Y: 200})
+		}
+		gs.Update()
+	}
+	return recorder.Replay()
+}
+
+func playBack(replay *core.Replay) error {
+	gs := core.NewGameState(core.WithSeed(replay.Seed))
+	player := core.NewPlayer(replay, gs)
+	for {
+		if err := player.Step(); err != nil {
+			return err
+		}
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	replay := recordGame(t)
+	if len(replay.Commands) != 8 || !replay.Commands[5].Failed {
+		t.Fatalf("Expected 8 commands with the sell failing, got %+v", replay.Commands)
+	}
+	if len(replay.Checkpoints) == 0 {
+		t.Fatal("Expected checkpoints to be recorded")
+	}
+
+	path := filepath.Join(t.TempDir(), "game.replay.json")
+	if err := replay.Save(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded, err := core.LoadReplay(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.Commands[0].Command != (core.BuildCommand{Tower: core.BasicTower, X: 225, Y: 275}) {
+		t.Errorf("Expected build command to survive the round trip, got %+v", loaded.Commands[0].Command)
+	}
+
+	if err := playBack(loaded); !errors.Is(err, core.ErrReplayFinished) {
+		t.Errorf("Expected the replay to finish cleanly, got %v", err)
+	}
+}
+
+func TestReplayDetectsDivergence(t *testing.T) {
+	replay := recordGame(t)
+	replay.Commands[0].Command = core.BuildCommand{Tower: core.BasicTower, X: 425, Y: 275}
+
+	var divergence *core.DivergenceError
+	if err := playBack(replay); !errors.As(err, &divergence) {
+		t.Errorf("Expected a divergence, got %v", err)
+	}
+
+	replay = recordGame(t)
+	replay.Seed++
+	if err := playBack(replay); !errors.As(err, &divergence) {
+		t.Errorf("Expected a divergence with the wrong seed, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+// effectTowers is a catalogue with slowing and poisoning towers and one
+// that fires projectiles.
+func effectTowers(t *testing.T) *core.TowerRegistry {
+	t.Helper()
+	registry, err := core.NewTowerRegistry([]core.TowerDefinition{
+		{ID: core.BasicTower, Name: "Basic", Cost: 50, Range: 150, Damage: 10, FireRate: time.Second / 2, ProjectileSpeed: 6,
+			Upgrades: []core.UpgradeDefinition{{Cost: 40, Damage: 5}}},
+		{ID: core.FrostTower, Name: "Frost", Cost: 50, Range: 150, Damage: 1, FireRate: time.Second,
+			Effect: &core.EffectDefinition{Kind: entities.EffectSlow, Duration: 2 * time.Second, SpeedMultiplier: 0.5}},
+		{ID: core.PoisonTower, Name: "Poison", Cost: 50, Range: 150, Damage: 1, FireRate: time.Second,
+			Effect: &core.EffectDefinition{Kind: entities.EffectPoison, Duration: 3 * time.Second, TickDamage: 2, TickInterval: time.Second / 2, Stacking: entities.StackAdd, MaxStacks: 3}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return registry
+}
+
+func gruntWaves(t *testing.T) *core.WaveSet {
+	t.Helper()
+	waves, err := core.ParseWaves([]byte(`{
+		"enemies": {"grunt": {"health": 60, "speed": 1, "reward": 1, "damage": 1}},
+		"waves": [{"groups": [{"enemy": "grunt", "count": 20, "interval": "1s"}]}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return waves
+}
+
+// gameInProgress builds a game with an upgraded tower, poisoned and slowed
+// enemies on the path, shots in flight and more enemies waiting to spawn.
+func gameInProgress(t *testing.T, registry *core.TowerRegistry) *core.GameState {
+	t.Helper()
+	gs := core.NewGameState(core.WithSeed(7), core.WithTowerRegistry(registry), core.WithWaves(gruntWaves(t)))
+	gs.SetMoney(1000)
+	results := []<-chan core.CommandResult{
+		gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 225, Y: 275}),
+		gs.Submit(core.BuildCommand{Tower: core.FrostTower, X: 300, Y: 200}),
+		gs.Submit(core.BuildCommand{Tower: core.PoisonTower, X: 100, Y: 250}),
+		gs.Submit(core.UpgradeCommand{Tower: 1}),
+		gs.Submit(core.CallWaveCommand{}),
+	}
+	gs.Update()
+	for _, result := range results {
+		if r := <-result; r.Err != nil {
+			t.Fatalf("%s: unexpected error: %v", r.Command.Kind(), r.Err)
+		}
+	}
+	for i := 0; i < 400; i++ {
+		gs.Update()
+	}
+	return gs
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	registry := effectTowers(t)
+	gs := gameInProgress(t, registry)
+	s := gs.Snapshot()
+	if len(s.Enemies) == 0 || s.Queued == 0 {
+		t.Fatalf("Expected enemies on the path and in the queue, got %d and %d", len(s.Enemies), s.Queued)
+	}
+	slowed, poisoned := false, false
+	for _, enemy := range s.Enemies {
+		slowed = slowed || enemy.HasEffect(entities.EffectSlow)
+		poisoned = poisoned || enemy.HasEffect(entities.EffectPoison)
+	}
+	if len(s.Projectiles) == 0 || !slowed || !poisoned {
+		t.Fatalf("Expected shots in flight and slowed and poisoned enemies, got %d shots, slowed %t, poisoned %t", len(s.Projectiles), slowed, poisoned)
+	}
+
+	var buf bytes.Buffer
+	if err := gs.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded := core.NewGameState(core.WithSeed(99), core.WithTowerRegistry(registry), core.WithWaves(gruntWaves(t)))
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.GetTick() != gs.GetTick() || loaded.GetSeed() != 7 {
+		t.Errorf("Expected tick %d and seed 7, got %d and %d", gs.GetTick(), loaded.GetTick(), loaded.GetSeed())
+	}
+	if loaded.GetTowers()[0].Level != 2 {
+		t.Errorf("Expected the upgraded tower to keep its level, got %d", loaded.GetTowers()[0].Level)
+	}
+
+	for tick := 0; tick < 600; tick++ {
+		if loaded.StateHash() != gs.StateHash() {
+			t.Fatalf("Loaded game drifted from the original %d ticks after loading", tick)
+		}
+		gs.Update()
+		loaded.Update()
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	gs := core.NewGameState()
+	err := gs.Load(strings.NewReader(`{"version": 999}`))
+	if !errors.Is(err, core.ErrNewerSave) {
+		t.Errorf("Expected ErrNewerSave, got %v", err)
+	}
+}
+
+func TestLoadRejectsOtherLevel(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gameInProgress(t, effectTowers(t)).Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	level, err := core.ParseLevel([]byte(mazeMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithLevel(level))
+	money := gs.GetMoney()
+	if err := gs.Load(&buf); err == nil {
+		t.Fatal("Expected a save from another level to be rejected")
+	}
+	if gs.GetMoney() != money || gs.GetTick() != 0 {
+		t.Error("Expected a failed load to leave the game untouched")
+	}
+}
+
+func TestLoadMazeRestoresWalls(t *testing.T) {
+	level, err := core.ParseLevel([]byte(mazeMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithLevel(level), core.WithSeed(3))
+	gs.SetMoney(1000)
+	x, y := gs.GetGrid().TileCenter(3, 1)
+	result := gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: x, Y: y})
+	gs.Submit(core.CallWaveCommand{})
+	gs.Update()
+	if r := <-result; r.Err != nil {
+		t.Fatalf("Unexpected error: %v", r.Err)
+	}
+	for i := 0; i < 100; i++ {
+		gs.Update()
+	}
+
+	var buf bytes.Buffer
+	if err := gs.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded := core.NewGameState(core.WithLevel(level))
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Snapshot().Paths, gs.Snapshot().Paths) {
+		t.Error("Expected the loaded maze to route around the tower like the original")
+	}
+	for tick := 0; tick < 300; tick++ {
+		if loaded.StateHash() != gs.StateHash() {
+			t.Fatalf("Loaded game drifted from the original %d ticks after loading", tick)
+		}
+		gs.Update()
+		loaded.Update()
+	}
+}
+
+func TestLoadMigratesVersion1(t *testing.T) {
+	registry := effectTowers(t)
+	var buf bytes.Buffer
+	if err := gameInProgress(t, registry).Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Version 1 saves had no tower IDs.
+	var save map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &save); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	save["version"] = 1
+	delete(save, "next_tower_id")
+	for _, tower := range save["towers"].([]any) {
+		delete(tower.(map[string]any), "id")
+	}
+	old, _ := json.Marshal(save)
+
+	gs := core.NewGameState(core.WithTowerRegistry(registry), core.WithWaves(gruntWaves(t)))
+	if err := gs.Load(bytes.NewReader(old)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	towers := gs.Snapshot().Towers
+	for i, tower := range towers {
+		if tower.ID != uint64(i+1) {
+			t.Errorf("Expected tower %d to get ID %d, got %d", i, i+1, tower.ID)
+		}
+	}
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: 500, Y: 200})
+	gs.Update()
+	if towers = gs.Snapshot().Towers; towers[len(towers)-1].ID != uint64(len(towers)) {
+		t.Errorf("Expected a new tower to get the next ID, got %d", towers[len(towers)-1].ID)
+	}
+}
+
+func TestLoadRejectsCorruptMazeSave(t *testing.T) {
+	level, err := core.ParseLevel([]byte(mazeMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithLevel(level))
+	x, y := gs.GetGrid().TileCenter(3, 1)
+	gs.Submit(core.BuildCommand{Tower: core.BasicTower, X: x, Y: y})
+	gs.Submit(core.CallWaveCommand{})
+	for i := 0; i < 30; i++ {
+		gs.Update()
+	}
+	if len(gs.GetEnemies()) == 0 {
+		t.Fatal("Expected enemies in the maze")
+	}
+	var buf bytes.Buffer
+	if err := gs.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	straight := []any{[]int{0, 1}, []int{1, 1}, []int{2, 1}, []int{3, 1}, []int{4, 1}, []int{5, 1}, []int{6, 1}}
+	tests := []struct {
+		name    string
+		corrupt func(save map[string]any)
+	}{
+		{"empty route", func(save map[string]any) { save["maze_routes"] = []any{[]any{}} }},
+		{"missing route", func(save map[string]any) { save["maze_routes"] = []any{} }},
+		{"jump", func(save map[string]any) { save["maze_routes"] = []any{[]any{[]int{0, 1}, []int{6, 1}}} }},
+		{"through a tower", func(save map[string]any) { save["maze_routes"] = []any{straight} }},
+		{"wrong start", func(save map[string]any) { save["maze_routes"] = []any{straight[1:]} }},
+		{"no exit", func(save map[string]any) { save["maze_routes"] = []any{straight[:2]} }},
+		{"path index", func(save map[string]any) {
+			save["enemies"].([]any)[0].(map[string]any)["path_index"] = -1
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var save map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &save); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.corrupt(save)
+			data, _ := json.Marshal(save)
+
+			loaded := core.NewGameState(core.WithLevel(level))
+			before := loaded.StateHash()
+			if err := loaded.Load(bytes.NewReader(data)); err == nil {
+				t.Fatal("Expected the corrupt save to be rejected")
+			}
+			if loaded.StateHash() != before {
+				t.Error("Expected a failed load to leave the game untouched")
+			}
+			for i := 0; i < 10; i++ {
+				loaded.Update()
+			}
+		})
+	}
+}
+
+func TestLoadDropsQueuedCommands(t *testing.T) {
+	registry := effectTowers(t)
+	gs := gameInProgress(t, registry)
+	var buf bytes.Buffer
+	if err := gs.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	towers := len(gs.GetTowers())
+	sell := gs.Submit(core.SellCommand{Tower: 1})
+	if err := gs.Load(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r := <-sell; !errors.Is(r.Err, core.ErrGameLoaded) {
+		t.Errorf("Expected the queued sell to fail with ErrGameLoaded, got %v", r.Err)
+	}
+	gs.Update()
+	if gs.PendingCommands() != 0 || len(gs.GetTowers()) != towers {
+		t.Errorf("Expected no commands to reach the loaded game, %d pending and %d towers", gs.PendingCommands(), len(gs.GetTowers()))
+	}
+}
+
+func TestLoadKeepsEffectsFromSoldTowers(t *testing.T) {
+	registry := effectTowers(t)
+	gs := gameInProgress(t, registry)
+	sell := gs.Submit(core.SellCommand{Tower: 3}) // the poison tower
+	gs.Update()
+	if r := <-sell; r.Err != nil {
+		t.Fatalf("Unexpected error: %v", r.Err)
+	}
+
+	var buf bytes.Buffer
+	if err := gs.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded := core.NewGameState(core.WithTowerRegistry(registry), core.WithWaves(gruntWaves(t)))
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	poisoned := 0
+	for _, enemy := range loaded.GetEnemies() {
+		for _, effect := range enemy.Effects {
+			if effect.Kind != entities.EffectPoison {
+				continue
+			}
+			poisoned++
+			if effect.Source == nil || effect.Source.ID != 3 {
+				t.Errorf("Enemy %d: expected poison from tower 3, got %+v", enemy.ID, effect.Source)
+			}
+		}
+	}
+	if poisoned == 0 {
+		t.Fatal("Expected poisoned enemies after selling the poison tower")
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/entities"
+)
+
+func TestSnapshotIsACopy(t *testing.T) {
+	gs := core.NewGameState()
+	if err := gs.AddTower(core.BasicTower, 100, 100); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs.AddEnemy(entities.NewEnemy(100, 10, 1, 2, gs.GetEnemyPath()))
+
+	s := gs.Snapshot()
+	if len(s.Towers) != 1 || len(s.Enemies) != 1 {
+		t.Fatalf("Expected 1 tower and 1 enemy, got %d and %d", len(s.Towers), len(s.Enemies))
+	}
+	if s.Money != gs.GetMoney() || s.Lives != gs.GetLives() || s.Tick != gs.GetTick() {
+		t.Error("Expected snapshot economy and tick to match the game")
+	}
+	if len(s.Path) != len(gs.GetEnemyPath()) {
+		t.Errorf("Expected path of %d points, got %d", len(gs.GetEnemyPath()), len(s.Path))
+	}
+
+	x := s.Enemies[0].X
+	gs.Update()
+	gs.SellTower(0)
+	if s.Enemies[0].X != x || len(s.Towers) != 1 {
+		t.Error("Snapshot should not change when the game does")
+	}
+	if leader := s.LeadingEnemy(); leader == nil || leader.ID != s.Enemies[0].ID {
+		t.Error("Expected the only enemy to lead")
+	}
+}
+
+func TestSnapshotDuringUpdate(t *testing.T) {
+	gs := core.NewGameState()
+	gs.AddTower(core.BasicTower, 225, 275)
+	gs.NextWave()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			gs.Update()
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		s := gs.Snapshot()
+		for _, enemy := range s.Enemies {
+			_ = enemy.X + enemy.Y
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+	"time"
+	"tower-defense/internal/core"
+)
+
+func TestSpawnerReleasesOverTime(t *testing.T) {
+	spawner := core.NewSpawner()
+	spawner.Load([]core.PlannedGroup{
+		{Enemy: core.EnemyDefinition{Health: 1}, Count: 3, Interval: time.Second},
+		{Enemy: core.EnemyDefinition{Health: 2}, Count: 2, Interval: 250 * time.Millisecond, Delay: 1500 * time.Millisecond},
+	})
+	if spawner.Queued() != 5 {
+		t.Fatalf("Expected 5 queued, got %d", spawner.Queued())
+	}
+
+	var spawned []int
+	spawn := func(g core.PlannedGroup) { spawned = append(spawned, g.Enemy.Health) }
+	step := 250 * time.Millisecond
+
+	spawner.Update(0, spawn)
+	if len(spawned) != 1 {
+		t.Fatalf("Expected the first enemy immediately, got %d", len(spawned))
+	}
+	for i := 0; i < 4; i++ { // t = 1s
+		spawner.Update(step, spawn)
+	}
+	if len(spawned) != 2 {
+		t.Errorf("Expected 2 spawned after 1s, got %d", len(spawned))
+	}
+	for i := 0; i < 2; i++ { // t = 1.5s
+		spawner.Update(step, spawn)
+	}
+	if len(spawned) != 3 || spawned[2] != 2 {
+		t.Errorf("Expected delayed group to start at 1.5s, got %v", spawned)
+	}
+	for i := 0; i < 2; i++ { // t = 2s
+		spawner.Update(step, spawn)
+	}
+	if len(spawned) != 5 {
+		t.Errorf("Expected all 5 spawned after 2s, got %v", spawned)
+	}
+	if spawner.Queued() != 0 {
+		t.Errorf("Expected empty queue, got %d", spawner.Queued())
+	}
+}
+
+func TestSpawnerCountsSwarmMembers(t *testing.T) {
+	spawner := core.NewSpawner()
+	spawner.Load([]core.PlannedGroup{
+		{Enemy: core.EnemyDefinition{Health: 1, SwarmSize: 5}, Count: 6, Interval: time.Second},
+		{Enemy: core.EnemyDefinition{Health: 2}, Count: 2, Interval: time.Second},
+	})
+	if spawner.Queued() != 32 {
+		t.Fatalf("Expected 32 queued, got %d", spawner.Queued())
+	}
+	spawner.Update(0, func(core.PlannedGroup) {})
+	if spawner.Queued() != 26 {
+		t.Errorf("Expected 26 queued after the first spawns, got %d", spawner.Queued())
+	}
+}
+
+func TestSpawnerCatchesUpOnLongTicks(t *testing.T) {
+	spawner := core.NewSpawner()
+	spawner.Load([]core.PlannedGroup{{Count: 10, Interval: 100 * time.Millisecond}})
+
+	count := 0
+	spawner.Update(time.Second, func(core.PlannedGroup) { count++ })
+	if count != 10 {
+		t.Errorf("Expected all 10 enemies within one long tick, got %d", count)
+	}
+}
+
+func TestWaveSpawnsGradually(t *testing.T) {
+	gs := core.NewGameState(core.WithClock(core.NewClock(time.Second / 10)))
+	gs.NextWave()
+	gs.NextWave() // wave 2: 4 enemies, one every 500ms
+
+	if len(gs.GetEnemies()) != 2 || gs.GetQueuedEnemies() != 4 {
+		t.Fatalf("Expected 2 spawned and 4 queued, got %d and %d", len(gs.GetEnemies()), gs.GetQueuedEnemies())
+	}
+	if gs.IsWaveComplete() {
+		t.Error("Wave should not be complete with enemies queued")
+	}
+
+	first := gs.GetEnemies()[1]
+	for i := 0; i < 5; i++ {
+		gs.Update()
+	}
+	enemies := gs.GetEnemies()
+	if len(enemies) != 4 || gs.GetQueuedEnemies() != 2 {
+		t.Fatalf("Expected 4 spawned and 2 queued after 500ms, got %d and %d", len(enemies), gs.GetQueuedEnemies())
+	}
+	newest := enemies[len(enemies)-1]
+	if first.X == newest.X && first.Y == newest.Y {
+		t.Error("Expected enemies spawned at different times not to overlap")
+	}
+}
+
+func TestWaveAdvancesOnlyWhenResolved(t *testing.T) {
+	gs := core.NewGameState()
+	gs.NextWave()
+	if gs.GetWave() != 1 {
+		t.Fatalf("Expected wave 1, got %d", gs.GetWave())
+	}
+
+	gs.SetEnemies(nil)
+	gs.Update()
+	if gs.GetWave() != 1 {
+		t.Error("Wave should not advance while enemies are still queued")
+	}
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"tower-defense/internal/core"
+)
+
+func TestShippedWavesLoad(t *testing.T) {
+	waves, err := core.LoadWaves("../../../configs/ennemy_waves.json")
+	if err != nil {
+		t.Fatalf("Unexpected error loading waves: %v", err)
+	}
+	if len(waves.Waves) == 0 {
+		t.Fatal("Expected at least one scripted wave")
+	}
+	if err := waves.ValidateSpawns(1); err != nil {
+		t.Errorf("Unexpected spawn error: %v", err)
+	}
+}
+
+func TestWavePlan(t *testing.T) {
+	waves, err := core.ParseWaves([]byte(`{
+		"enemies": {"grunt": {"health": 50, "speed": 1, "reward": 10, "damage": 1}},
+		"waves": [{
+			"reward_multiplier": 2,
+			"groups": [
+				{"enemy": "grunt", "count": 3, "interval": "500ms", "delay": "1s", "reward_multiplier": 1.5}
+			]
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plan := waves.Plan(1)
+	if len(plan) != 1 {
+		t.Fatalf("Expected 1 group, got %d", len(plan))
+	}
+	group := plan[0]
+	if group.Count != 3 || group.Interval != 500*time.Millisecond || group.Delay != time.Second {
+		t.Errorf("Unexpected group %+v", group)
+	}
+	if group.Enemy.Reward != 30 {
+		t.Errorf("Expected reward 10 * 2 * 1.5 = 30, got %d", group.Enemy.Reward)
+	}
+
+	endless := waves.Plan(7)
+	if len(endless) != 1 || endless[0].Count != 14 || endless[0].Enemy.Health != 120 {
+		t.Errorf("Expected wave 7 to fall back to the endless formula, got %+v", endless)
+	}
+}
+
+func TestWaveValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		errMsg string
+	}{
+		{
+			"unknown enemy",
+			`{"enemies": {"grunt": {"health": 1, "speed": 1}}, "waves": [
+				{"groups": [{"enemy": "grunt", "count": 1}]},
+				{"groups": [{"enemy": "ghost", "count": 1}]}]}`,
+			`wave 2, group 1: unknown enemy "ghost"`,
+		},
+		{
+			"zero count",
+			`{"enemies": {"grunt": {"health": 1, "speed": 1}}, "waves": [
+				{"groups": [{"enemy": "grunt", "count": 1}, {"enemy": "grunt", "count": 0}]}]}`,
+			"wave 1, group 2: count must be positive",
+		},
+		{
+			"empty wave",
+			`{"enemies": {}, "waves": [{"groups": []}]}`,
+			"wave 1: no groups",
+		},
+		{
+			"bad enemy",
+			`{"enemies": {"grunt": {"health": 0, "speed": 1}}, "waves": []}`,
+			`enemy "grunt": health must be positive`,
+		},
+		{
+			"bad duration",
+			`{"enemies": {"grunt": {"health": 1, "speed": 1}}, "waves": [
+				{"groups": [{"enemy": "grunt", "count": 1, "interval": "soon"}]}]}`,
+			"parse waves",
+		},
+		{
+			"unknown field",
+			`{"enemies": {"grunt": {"health": 1, "speed": 1, "armour": 3}}, "waves": []}`,
+			"armour",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := core.ParseWaves([]byte(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+
+	waves, _ := core.ParseWaves([]byte(`{"enemies": {"grunt": {"health": 1, "speed": 1}}, "waves": [
+		{"groups": [{"enemy": "grunt", "count": 1, "spawn": 2}]}]}`))
+	if err := waves.ValidateSpawns(1); err == nil || !strings.Contains(err.Error(), "wave 1, group 1") {
+		t.Errorf("Expected spawn error pointing at wave 1, got %v", err)
+	}
+}
+
+func TestNextWaveUsesWaveSet(t *testing.T) {
+	waves, err := core.ParseWaves([]byte(`{
+		"enemies": {"grunt": {"health": 77, "speed": 1, "reward": 5, "damage": 2}},
+		"waves": [{"groups": [{"enemy": "grunt", "count": 5}]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithWaves(waves))
+
+	gs.NextWave()
+	enemies := gs.GetEnemies()
+	if len(enemies)+gs.GetQueuedEnemies() != 5 {
+		t.Fatalf("Expected 5 enemies spawned or queued, got %d and %d", len(enemies), gs.GetQueuedEnemies())
+	}
+	if enemies[0].Health != 77 || enemies[0].Reward != 5 || enemies[0].Damage != 2 {
+		t.Errorf("Expected grunt stats, got %+v", enemies[0])
+	}
+}
+
+func TestArchetypesSpawnWithTraits(t *testing.T) {
+	waves, err := core.ParseWaves([]byte(`{
+		"enemies": {
+			"bat": {"health": 10, "speed": 1, "flying": true},
+			"tank": {"health": 500, "speed": 1, "armor": 5, "boss": true},
+			"swarm": {"health": 5, "speed": 1, "swarm_size": 4}
+		},
+		"waves": [{"groups": [
+			{"enemy": "bat", "count": 1},
+			{"enemy": "tank", "count": 1},
+			{"enemy": "swarm", "count": 1}
+		]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gs := core.NewGameState(core.WithWaves(waves))
+	gs.NextWave()
+
+	counts := map[string]int{}
+	for _, e := range gs.GetEnemies() {
+		counts[e.Archetype]++
+		switch e.Archetype {
+		case "bat":
+			if !e.Flying {
+				t.Error("Expected bat to fly")
+			}
+		case "tank":
+			if e.Armor != 5 || !e.Boss {
+				t.Errorf("Expected armored boss tank, got armor %d boss %v", e.Armor, e.Boss)
+			}
+		}
+	}
+	if counts["bat"] != 1 || counts["tank"] != 1 || counts["swarm"] != 4 {
+		t.Errorf("Expected 1 bat, 1 tank and 4 swarm units, got %v", counts)
+	}
+}
@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"testing"
+	"tower-defense/internal/entities"
+)
+
+func TestCalculateDamage(t *testing.T) {
+	tests := []struct {
+		name        string
+		armor       int
+		resistances entities.Resistances
+		amount      int
+		damageType  entities.DamageType
+		expected    int
+	}{
+		{"plain", 0, nil, 10, entities.DamagePhysical, 10},
+		{"armor", 4, nil, 10, entities.DamagePhysical, 6},
+		{"armor floor", 20, nil, 10, entities.DamageExplosive, 1},
+		{"pierce ignores armor", 20, nil, 10, entities.DamagePierce, 10},
+		{"resistance", 0, entities.Resistances{entities.DamageMagic: 0.5}, 10, entities.DamageMagic, 5},
+		{"resistance other type", 0, entities.Resistances{entities.DamageMagic: 0.5}, 10, entities.DamagePhysical, 10},
+		{"resistance then armor", 2, entities.Resistances{entities.DamagePhysical: 0.5}, 10, entities.DamagePhysical, 3},
+		{"weakness", 0, entities.Resistances{entities.DamageMagic: -0.5}, 10, entities.DamageMagic, 15},
+		{"immune", 0, entities.Resistances{entities.DamageExplosive: 1}, 10, entities.DamageExplosive, 0},
+		{"zero hit", 0, nil, 0, entities.DamagePhysical, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 0, Y: 0}})
+			e.Armor = tt.armor
+			e.Resistances = tt.resistances
+			if got := entities.CalculateDamage(e, tt.amount, tt.damageType); got != tt.expected {
+				t.Errorf("Expected %d damage, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTowerDamageGoesThroughResistances(t *testing.T) {
+	tower := entities.NewAOETower(0, 0)
+	tower.ProjectileSpeed = 0
+	target := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 10, Y: 0}})
+	target.Resistances = entities.Resistances{entities.DamageExplosive: 0.6}
+	bystander := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 20, Y: 0}})
+	bystander.Armor = 3
+	enemies := []*entities.Enemy{target, bystander}
+
+	tower.Update(enemies, 0, nil)
+	if target.Health != 94 {
+		t.Errorf("Expected 15 explosive damage reduced to 6, got Health %d", target.Health)
+	}
+	if bystander.Health != 96 {
+		t.Errorf("Expected 7 splash damage reduced by 3 armor, got Health %d", bystander.Health)
+	}
+}
+
+func TestDamageTypeIsValid(t *testing.T) {
+	for _, d := range []entities.DamageType{entities.DamagePhysical, entities.DamageMagic, entities.DamageExplosive, entities.DamagePierce} {
+		if !d.IsValid() {
+			t.Errorf("Expected %s to be valid", d)
+		}
+	}
+	if entities.DamageType("fire").IsValid() {
+		t.Error("Expected unknown damage type to be invalid")
+	}
+}
@@ -132,3 +132,32 @@ func TestGetDamage(t *testing.T) {
 		t.Errorf("Expected GetDamage to return 5, got %d", e.GetDamage())
 	}
 }
+
+func TestPathProgress(t *testing.T) {
+	path := []entities.BaseEntity{{X: 0, Y: 0}, {X: 30, Y: 40}, {X: 30, Y: 100}}
+	e := entities.NewEnemy(100, 10, 1, 10.0, path)
+
+	if e.PathLength() != 110 {
+		t.Errorf("Expected path length 110, got %f", e.PathLength())
+	}
+	if e.Progress() != 0 || e.RemainingDistance() != 110 {
+		t.Errorf("Expected no progress at spawn, got %f travelled and %f remaining", e.Progress(), e.RemainingDistance())
+	}
+
+	for i := 0; i < 7; i++ {
+		e.Move()
+	}
+	if e.Progress() != 70 {
+		t.Errorf("Expected progress 70 after 7 moves, got %f", e.Progress())
+	}
+	if e.RemainingDistance() != 40 {
+		t.Errorf("Expected 40 remaining after 7 moves, got %f", e.RemainingDistance())
+	}
+
+	for !e.HasReachedEnd() {
+		e.Move()
+	}
+	if e.RemainingDistance() != 0 {
+		t.Errorf("Expected nothing remaining at the exit, got %f", e.RemainingDistance())
+	}
+}
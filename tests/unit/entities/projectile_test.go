@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"testing"
+	"tower-defense/internal/entities"
+)
+
+func TestNewProjectile(t *testing.T) {
+	tower := entities.NewSniperTower(10, 20)
+	enemy := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 100, Y: 20}})
+	p := entities.NewProjectile(tower, enemy, 42)
+
+	if p.X != 10 || p.Y != 20 {
+		t.Errorf("Expected projectile to start at the tower, got (%f,%f)", p.X, p.Y)
+	}
+	if p.Speed != tower.ProjectileSpeed || p.Homing != tower.Homing {
+		t.Error("Expected projectile to inherit speed and homing from its tower")
+	}
+	if p.Damage != 42 || p.Target != enemy || p.Source != tower {
+		t.Error("Expected projectile to carry its damage, target and source")
+	}
+	if p.AimX != 100 || p.AimY != 20 {
+		t.Errorf("Expected projectile to aim at the target, got (%f,%f)", p.AimX, p.AimY)
+	}
+}
+
+func TestProjectileTravelTime(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	tower.ProjectileSpeed = 10
+	enemy := entities.NewEnemy(100, 10, 5, 0, []entities.BaseEntity{{X: 35, Y: 0}})
+	enemies := []*entities.Enemy{enemy}
+	p := entities.NewProjectile(tower, enemy, 10)
+
+	for i := 0; i < 3; i++ {
+		p.Update(enemies)
+		if p.Done {
+			t.Fatalf("Projectile arrived after %d ticks, expected 4", i+1)
+		}
+	}
+	if p.X != 30 {
+		t.Errorf("Expected projectile at x=30 after 3 ticks, got %f", p.X)
+	}
+	p.Update(enemies)
+	if !p.Done || !p.Hit {
+		t.Error("Expected projectile to hit on the 4th tick")
+	}
+	if enemy.Health != 90 {
+		t.Errorf("Expected enemy Health 90, got %d", enemy.Health)
+	}
+}
+
+func TestProjectileMissesFastEnemy(t *testing.T) {
+	tower := entities.NewSniperTower(0, 0)
+	tower.ProjectileSpeed = 2
+	path := []entities.BaseEntity{{X: 0, Y: 50}, {X: 1000, Y: 50}}
+	enemy := entities.NewEnemy(100, 10, 5, 5.0, path)
+	enemies := []*entities.Enemy{enemy}
+	p := entities.NewProjectile(tower, enemy, 30)
+
+	for !p.Done {
+		p.Update(enemies)
+		enemy.Move()
+	}
+	if p.Hit {
+		t.Error("Expected slow non-homing projectile to miss a fast enemy")
+	}
+	if enemy.Health != 100 {
+		t.Errorf("Expected enemy to be unharmed, got Health %d", enemy.Health)
+	}
+}
+
+func TestHomingProjectileFollowsTarget(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	tower.ProjectileSpeed = 6
+	path := []entities.BaseEntity{{X: 0, Y: 50}, {X: 1000, Y: 50}}
+	enemy := entities.NewEnemy(100, 10, 5, 3.0, path)
+	enemies := []*entities.Enemy{enemy}
+	p := entities.NewProjectile(tower, enemy, 10)
+
+	for i := 0; i < 1000 && !p.Done; i++ {
+		p.Update(enemies)
+		enemy.Move()
+	}
+	if !p.Hit {
+		t.Error("Expected homing projectile to hit its target")
+	}
+}
+
+func TestProjectileWastedOnDeadTarget(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	enemy := entities.NewEnemy(100, 10, 5, 0, []entities.BaseEntity{{X: 50, Y: 0}})
+	enemies := []*entities.Enemy{enemy}
+	p := entities.NewProjectile(tower, enemy, 10)
+
+	enemy.TakeDamage(100)
+	for !p.Done {
+		p.Update(enemies)
+	}
+	if p.Hit {
+		t.Error("Expected projectile not to hit an already dead enemy")
+	}
+}
+
+func TestProjectileMissesRecycledTarget(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	tower.ProjectileSpeed = 10
+	enemy := entities.NewEnemy(100, 10, 5, 0, []entities.BaseEntity{{X: 20, Y: 0}})
+	enemy.ID = 1
+	p := entities.NewProjectile(tower, enemy, 10)
+
+	// The enemy dies and its struct is reused for a new one in the same spot.
+	enemy.Init(100, 10, 5, 0, []entities.BaseEntity{{X: 20, Y: 0}})
+	enemy.ID = 2
+
+	for i := 0; i < 3 && !p.Done; i++ {
+		p.Update([]*entities.Enemy{enemy})
+	}
+	if !p.Done || p.Hit {
+		t.Error("Expected projectile to miss an enemy that replaced its target")
+	}
+	if enemy.Health != 100 {
+		t.Errorf("Expected the new enemy to be untouched, got Health %d", enemy.Health)
+	}
+}
+
+func TestLaunchResetsProjectile(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	enemy := entities.NewEnemy(100, 10, 5, 0, []entities.BaseEntity{{X: 20, Y: 0}})
+	p := entities.NewProjectile(tower, enemy, 10)
+	p.Done, p.Hit, p.X = true, true, 99
+
+	p.Launch(tower, enemy, 7)
+	if p.Done || p.Hit || p.X != 0 || p.Damage != 7 {
+		t.Errorf("Expected Launch to start a fresh shot, got %+v", *p)
+	}
+}
@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"testing"
+	"time"
+	"tower-defense/internal/entities"
+)
+
+func newTestEnemy() *entities.Enemy {
+	return entities.NewEnemy(100, 10, 1, 2.0, []entities.BaseEntity{{X: 0, Y: 0}, {X: 1000, Y: 0}})
+}
+
+func TestSlowReducesSpeed(t *testing.T) {
+	e := newTestEnemy()
+	e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectSlow, Duration: time.Second, SpeedMultiplier: 0.5})
+
+	e.Move()
+	if e.X != 1 {
+		t.Errorf("Expected slowed enemy to move 1 unit, got %f", e.X)
+	}
+
+	e.UpdateEffects(time.Second)
+	if e.HasEffect(entities.EffectSlow) {
+		t.Error("Expected slow to expire after its duration")
+	}
+	e.Move()
+	if e.X != 3 {
+		t.Errorf("Expected enemy back at full speed, got x=%f", e.X)
+	}
+}
+
+func TestStunStopsMovement(t *testing.T) {
+	e := newTestEnemy()
+	e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectStun, Duration: time.Second})
+	e.Move()
+	if e.X != 0 {
+		t.Errorf("Expected stunned enemy not to move, got x=%f", e.X)
+	}
+}
+
+func TestRefreshStacking(t *testing.T) {
+	e := newTestEnemy()
+	e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectSlow, Duration: time.Second, SpeedMultiplier: 0.8, Stacking: entities.StackRefresh})
+	e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectSlow, Duration: 500 * time.Millisecond, SpeedMultiplier: 0.5, Stacking: entities.StackRefresh})
+
+	if len(e.Effects) != 1 {
+		t.Fatalf("Expected refreshed slow to stay a single effect, got %d", len(e.Effects))
+	}
+	if e.Effects[0].Duration != time.Second || e.SpeedMultiplier() != 0.5 {
+		t.Errorf("Expected longest duration and strongest slow, got %v and %f", e.Effects[0].Duration, e.SpeedMultiplier())
+	}
+}
+
+func TestPoisonStacksAndTicks(t *testing.T) {
+	e := newTestEnemy()
+	poison := entities.StatusEffect{
+		Kind:         entities.EffectPoison,
+		Duration:     2 * time.Second,
+		TickDamage:   3,
+		TickInterval: 500 * time.Millisecond,
+		DamageType:   entities.DamageMagic,
+		Stacking:     entities.StackAdd,
+		MaxStacks:    2,
+	}
+	e.ApplyEffect(poison)
+	e.ApplyEffect(poison)
+	e.ApplyEffect(poison)
+	if len(e.Effects) != 2 {
+		t.Fatalf("Expected poison capped at 2 stacks, got %d", len(e.Effects))
+	}
+
+	for i := 0; i < 4; i++ {
+		e.UpdateEffects(500 * time.Millisecond)
+	}
+	if e.Health != 100-2*4*3 {
+		t.Errorf("Expected 2 stacks x 4 ticks x 3 damage, got Health %d", e.Health)
+	}
+	if len(e.Effects) != 0 {
+		t.Errorf("Expected poison to have worn off, got %d effects", len(e.Effects))
+	}
+}
+
+func TestTickDamageUsesResistances(t *testing.T) {
+	e := newTestEnemy()
+	e.Resistances = entities.Resistances{entities.DamageMagic: 0.5}
+	e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectBurn, Duration: time.Second, TickDamage: 10, TickInterval: time.Second, DamageType: entities.DamageMagic})
+	e.UpdateEffects(time.Second)
+	if e.Health != 95 {
+		t.Errorf("Expected burn halved by magic resistance, got Health %d", e.Health)
+	}
+}
+
+func TestEffectImmunity(t *testing.T) {
+	e := newTestEnemy()
+	e.Immunities = []entities.EffectKind{entities.EffectStun}
+	if e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectStun, Duration: time.Second}) {
+		t.Error("Expected immune enemy to reject stun")
+	}
+	if !e.ApplyEffect(entities.StatusEffect{Kind: entities.EffectSlow, Duration: time.Second, SpeedMultiplier: 0.5}) {
+		t.Error("Expected stun immunity not to block slow")
+	}
+}
+
+func TestFrostAndPoisonTowersApplyEffects(t *testing.T) {
+	for _, tower := range []*entities.Tower{entities.NewFrostTower(0, 0), entities.NewPoisonTower(0, 0)} {
+		tower.ProjectileSpeed = 0
+		e := entities.NewEnemy(100, 10, 1, 1.0, []entities.BaseEntity{{X: 10, Y: 0}})
+		tower.Update([]*entities.Enemy{e}, 0, nil)
+		if !e.HasEffect(tower.Effect.Kind) {
+			t.Errorf("Expected %s tower to apply %s", tower.Type, tower.Effect.Kind)
+		}
+	}
+}
+
+func TestPoisonCreditsKillToTower(t *testing.T) {
+	tower := entities.NewPoisonTower(0, 0)
+	tower.ProjectileSpeed = 0
+	e := entities.NewEnemy(3, 10, 1, 0, []entities.BaseEntity{{X: 10, Y: 0}})
+	tower.Update([]*entities.Enemy{e}, 0, nil)
+	if e.Health <= 0 || e.KilledBy != nil {
+		t.Fatalf("Expected enemy to survive the hit, health %d", e.Health)
+	}
+
+	e.UpdateEffects(time.Second)
+	if e.Health > 0 {
+		t.Fatalf("Expected poison to kill the enemy, health %d", e.Health)
+	}
+	if e.KilledBy != tower {
+		t.Error("Expected the poison tower to be credited with the kill")
+	}
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"testing"
+	"tower-defense/internal/entities"
+)
+
+func TestTargetingStrategies(t *testing.T) {
+	path := []entities.BaseEntity{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}}
+	tower := entities.NewBasicTower(100, 40)
+	tower.Range = 200
+
+	// leader has walked furthest along the path, even though it is closer
+	// to the start in straight-line terms.
+	leader := entities.NewEnemy(50, 10, 1, 1.0, path)
+	leader.X, leader.Y, leader.PathIndex, leader.Distance = 100, 30, 1, 130
+	middle := entities.NewEnemy(300, 10, 1, 1.0, path)
+	middle.X, middle.Y, middle.Distance = 90, 0, 90
+	trailer := entities.NewEnemy(100, 10, 1, 1.0, path)
+	trailer.X, trailer.Y, trailer.Distance = 20, 0, 20
+	enemies := []*entities.Enemy{trailer, middle, leader}
+
+	tests := []struct {
+		strategy string
+		expected *entities.Enemy
+	}{
+		{"first", leader},
+		{"last", trailer},
+		{"strongest", middle},
+		{"weakest", leader},
+		{"closest", leader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			strategy, ok := entities.TargetingByName(tt.strategy)
+			if !ok {
+				t.Fatalf("Expected strategy %q to exist", tt.strategy)
+			}
+			if strategy.Name() != tt.strategy {
+				t.Errorf("Expected Name %q, got %q", tt.strategy, strategy.Name())
+			}
+			tower.Targeting = strategy
+			if got := tower.SelectTarget(enemies); got != tt.expected {
+				t.Errorf("Expected %q to pick enemy with health %d, got %v", tt.strategy, tt.expected.Health, got)
+			}
+		})
+	}
+
+	if _, ok := entities.TargetingByName("random"); ok {
+		t.Error("Expected unknown strategy lookup to fail")
+	}
+}
+
+func TestSelectTargetIgnoresOutOfRangeAndDead(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	far := entities.NewEnemy(100, 10, 1, 1.0, []entities.BaseEntity{{X: 500, Y: 0}})
+	dead := entities.NewEnemy(100, 10, 1, 1.0, []entities.BaseEntity{{X: 10, Y: 0}})
+	dead.TakeDamage(100)
+
+	if got := tower.SelectTarget([]*entities.Enemy{far, dead}); got != nil {
+		t.Errorf("Expected no target, got %v", got)
+	}
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"math/rand"
 	"testing"
 	"time"
 	"tower-defense/internal/entities"
@@ -93,7 +94,15 @@ func TestCanFire(t *testing.T) {
 		t.Error("Expected CanFire to be false immediately after firing")
 	}
 
-	time.Sleep(tower.FireRate + time.Millisecond)
+	dt := time.Second / 60
+	for elapsed := dt; elapsed < tower.FireRate; elapsed += dt {
+		tower.Tick(dt)
+		if tower.CanFire() {
+			t.Fatalf("Expected CanFire to be false after %v, FireRate is %v", elapsed, tower.FireRate)
+		}
+	}
+
+	tower.Tick(dt)
 	if !tower.CanFire() {
 		t.Error("Expected CanFire to be true after FireRate duration")
 	}
@@ -159,7 +168,14 @@ func TestUpdate(t *testing.T) {
 	enemy2 := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 200, Y: 0}})
 	enemies := []*entities.Enemy{enemy1, enemy2}
 
-	tower.Update(enemies)
+	projectile := tower.Update(enemies, time.Second/60, nil)
+	if projectile == nil {
+		t.Fatal("Expected tower to launch a projectile")
+	}
+	if enemy1.Health != 100 {
+		t.Errorf("Expected enemy1 to be untouched while the projectile is in flight, got %d", enemy1.Health)
+	}
+	resolveProjectile(t, projectile, enemies)
 	if enemy1.Health != 90 {
 		t.Errorf("Expected enemy1 Health to be 90, got %d", enemy1.Health)
 	}
@@ -167,7 +183,9 @@ func TestUpdate(t *testing.T) {
 		t.Errorf("Expected enemy2 Health to be 100, got %d", enemy2.Health)
 	}
 
-	tower.Update(enemies) // Should not fire due to fire rate
+	if tower.Update(enemies, time.Second/60, nil) != nil {
+		t.Error("Expected no projectile while the tower is cooling down")
+	}
 	if enemy1.Health != 90 {
 		t.Errorf("Expected enemy1 Health to still be 90, got %d", enemy1.Health)
 	}
@@ -205,3 +223,98 @@ func TestDealAOEDamage(t *testing.T) {
 		t.Errorf("Expected enemy2 Health to be 100, got %d", enemy2.Health)
 	}
 }
+
+func TestCriticalHits(t *testing.T) {
+	tower := entities.NewSniperTower(0, 0)
+	tower.CritChance = 1
+	enemy := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 50, Y: 0}})
+
+	enemies := []*entities.Enemy{enemy}
+	resolveProjectile(t, tower.Update(enemies, time.Second/60, rand.New(rand.NewSource(1))), enemies)
+	if enemy.Health != 100-tower.Damage*2 {
+		t.Errorf("Expected a critical hit for %d, got health %d", tower.Damage*2, enemy.Health)
+	}
+
+	tower = entities.NewSniperTower(0, 0)
+	tower.CritChance = 1
+	enemy.Health = 100
+	resolveProjectile(t, tower.Update(enemies, time.Second/60, nil), enemies)
+	if enemy.Health != 100-tower.Damage {
+		t.Errorf("Expected no critical hit without a random source, got health %d", enemy.Health)
+	}
+}
+
+func TestInstantHitTower(t *testing.T) {
+	tower := entities.NewBasicTower(0, 0)
+	tower.ProjectileSpeed = 0
+	enemy := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 50, Y: 0}})
+
+	if tower.Update([]*entities.Enemy{enemy}, time.Second/60, nil) != nil {
+		t.Error("Expected no projectile from an instant-hit tower")
+	}
+	if enemy.Health != 100-tower.Damage {
+		t.Errorf("Expected instant damage, got health %d", enemy.Health)
+	}
+}
+
+func resolveProjectile(t *testing.T, p *entities.Projectile, enemies []*entities.Enemy) {
+	t.Helper()
+	if p == nil {
+		t.Fatal("Expected a projectile")
+	}
+	for i := 0; i < 1000 && !p.Done; i++ {
+		p.Update(enemies)
+	}
+	if !p.Done {
+		t.Fatal("Projectile never resolved")
+	}
+}
+
+func TestCanTarget(t *testing.T) {
+	path := []entities.BaseEntity{{X: 10, Y: 0}}
+	ground := entities.NewEnemy(100, 10, 5, 1.0, path)
+	ground.Archetype = "normal"
+	flyer := entities.NewEnemy(100, 10, 5, 1.0, path)
+	flyer.Archetype, flyer.Flying = "flying", true
+	boss := entities.NewEnemy(100, 10, 5, 1.0, path)
+	boss.Archetype, boss.Boss = "boss", true
+
+	tests := []struct {
+		name   string
+		canHit []string
+		hits   []bool // ground, flyer, boss
+	}{
+		{"anything", nil, []bool{true, true, true}},
+		{"ground only", []string{"ground"}, []bool{true, false, true}},
+		{"air only", []string{"air"}, []bool{false, true, false}},
+		{"named archetype", []string{"boss"}, []bool{false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tower := entities.NewBasicTower(0, 0)
+			tower.CanHit = tt.canHit
+			for i, e := range []*entities.Enemy{ground, flyer, boss} {
+				if tower.CanTarget(e) != tt.hits[i] {
+					t.Errorf("CanTarget(%s) = %v, expected %v", e.Archetype, !tt.hits[i], tt.hits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGroundTowerIgnoresFlyers(t *testing.T) {
+	tower := entities.NewAOETower(0, 0)
+	flyer := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 10, Y: 0}})
+	flyer.Flying = true
+	walker := entities.NewEnemy(100, 10, 5, 1.0, []entities.BaseEntity{{X: 40, Y: 0}})
+	enemies := []*entities.Enemy{flyer, walker}
+
+	if got := tower.SelectTarget(enemies); got != walker {
+		t.Error("Expected ground-only tower to skip the flying enemy")
+	}
+	tower.DealAOEDamage(enemies, walker)
+	if flyer.Health != 100 {
+		t.Errorf("Expected splash to miss the flying enemy, got Health %d", flyer.Health)
+	}
+}
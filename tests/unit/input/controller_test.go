@@ -0,0 +1,108 @@
+package input
+
+import (
+	"errors"
+	"testing"
+	"tower-defense/internal/core"
+	"tower-defense/internal/input"
+)
+
+// press feeds keys to the controller one at a time, as a player would, and
+// returns what the last one asked for.
+func press(c *input.Controller, s *core.Snapshot, keys ...input.Key) (core.Command, input.Action, error) {
+	var (
+		cmd    core.Command
+		action input.Action
+		err    error
+	)
+	for _, key := range keys {
+		cmd, action, err = c.Handle(key, s)
+	}
+	return cmd, action, err
+}
+
+func TestControllerBuildsAtCursor(t *testing.T) {
+	gs := core.NewGameState()
+	s := gs.Snapshot()
+	c := input.NewController(s.Grid)
+	col, row := c.Col, c.Row
+
+	cmd, _, err := press(c, s, input.KeyRight, input.KeyRight, input.KeyUp, '2', 'B')
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	x, y := s.Grid.TileCenter(col+2, row-1)
+	want := core.BuildCommand{Tower: s.TowerDefinitions[1].ID, X: x, Y: y}
+	if cmd != want {
+		t.Errorf("Expected %+v, got %+v", want, cmd)
+	}
+}
+
+func TestControllerCursorStaysOnGrid(t *testing.T) {
+	s := core.NewGameState().Snapshot()
+	c := input.NewController(s.Grid)
+	for i := 0; i < s.Grid.Cols+5; i++ {
+		press(c, s, input.KeyLeft, input.KeyUp)
+	}
+	if c.Col != 0 || c.Row != 0 {
+		t.Errorf("Expected the cursor to stop at (0,0), got (%d,%d)", c.Col, c.Row)
+	}
+}
+
+func TestControllerActsOnTowerUnderCursor(t *testing.T) {
+	gs := core.NewGameState()
+	if err := gs.AddTower(core.BasicTower, 225, 275); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := gs.AddTower(core.BasicTower, 300, 200); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := gs.Snapshot()
+	c := input.NewController(s.Grid)
+
+	if _, _, err := press(c, s, 'u'); !errors.Is(err, input.ErrNoTower) {
+		t.Errorf("Expected ErrNoTower away from the towers, got %v", err)
+	}
+
+	c.Col, c.Row = s.Grid.TileAt(300, 200)
+	tests := []struct {
+		key  input.Key
+		want core.Command
+	}{
+		{'u', core.UpgradeCommand{Tower: s.Towers[1].ID}},
+		{'S', core.SellCommand{Tower: s.Towers[1].ID}},
+		{'t', core.SetTargetingCommand{Tower: s.Towers[1].ID, Strategy: "last"}},
+	}
+	for _, tt := range tests {
+		cmd, _, err := c.Handle(tt.key, s)
+		if err != nil || cmd != tt.want {
+			t.Errorf("Key %q: expected %+v, got %+v (%v)", tt.key, tt.want, cmd, err)
+		}
+	}
+}
+
+func TestControllerKeys(t *testing.T) {
+	s := core.NewGameState().Snapshot()
+	tests := []struct {
+		key    input.Key
+		cmd    core.Command
+		action input.Action
+		err    error
+	}{
+		{'p', core.PauseCommand{}, input.ActionNone, nil},
+		{'n', core.CallWaveCommand{}, input.ActionNone, nil},
+		{'w', nil, input.ActionSave, nil},
+		{'L', nil, input.ActionLoad, nil},
+		{'q', nil, input.ActionQuit, nil},
+		{input.KeyCtrlC, nil, input.ActionQuit, nil},
+		{'9', nil, input.ActionNone, input.ErrUnknownKey},
+		{'z', nil, input.ActionNone, input.ErrUnknownKey},
+	}
+	for _, tt := range tests {
+		c := input.NewController(s.Grid)
+		cmd, action, err := c.Handle(tt.key, s)
+		if cmd != tt.cmd || action != tt.action || !errors.Is(err, tt.err) {
+			t.Errorf("Key %q: expected %v, %v, %v; got %v, %v, %v", tt.key, tt.cmd, tt.action, tt.err, cmd, action, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package input
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"tower-defense/internal/input"
+)
+
+func collect(keys <-chan input.Key) []input.Key {
+	var got []input.Key
+	for key := range keys {
+		got = append(got, key)
+	}
+	return got
+}
+
+func TestReadKeys(t *testing.T) {
+	got := collect(input.ReadKeys(strings.NewReader("b\x1b[A\x1b[Dq\x1b\x03")))
+	want := []input.Key{'b', input.KeyUp, input.KeyLeft, 'q', input.KeyEscape, input.KeyCtrlC}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestReadKeysOneAtATime(t *testing.T) {
+	r, w := io.Pipe()
+	keys := input.ReadKeys(r)
+	for _, tt := range []struct {
+		typed string
+		want  input.Key
+	}{
+		{"u", 'u'},
+		{"\x1b[B", input.KeyDown},
+		{"\x1b", input.KeyEscape}, // nothing follows yet, so a plain escape
+		{"[C", '['},
+	} {
+		go w.Write([]byte(tt.typed))
+		if key := <-keys; key != tt.want {
+			t.Errorf("Typing %q: expected %v, got %v", tt.typed, tt.want, key)
+		}
+	}
+	<-keys // the C left over from the last write
+	w.Close()
+	if _, ok := <-keys; ok {
+		t.Error("Expected the channel to close when the input ends")
+	}
+}
+
+func TestMakeRawNeedsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "not-a-terminal"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.Close()
+	if _, err := input.MakeRaw(f); err == nil {
+		t.Error("Expected MakeRaw to fail on a regular file")
+	}
+}
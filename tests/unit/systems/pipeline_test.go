@@ -0,0 +1,90 @@
+package systems
+
+import (
+	"reflect"
+	"testing"
+	"tower-defense/internal/entities"
+	"tower-defense/internal/systems"
+	"tower-defense/internal/utils"
+)
+
+type recorder struct {
+	name string
+	log  *[]string
+}
+
+func (r recorder) Name() string { return r.name }
+
+func (r recorder) Update(w *systems.World) { *r.log = append(*r.log, r.name) }
+
+func TestPipelineRunsInOrder(t *testing.T) {
+	var log []string
+	p := systems.NewPipeline(recorder{"a", &log}, recorder{"c", &log})
+	if err := p.InsertBefore("c", recorder{"b", &log}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.Add(recorder{"d", &log})
+
+	p.Update(&systems.World{})
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Expected %v, got %v", want, log)
+	}
+	if !reflect.DeepEqual(p.Names(), log) {
+		t.Errorf("Expected names %v, got %v", log, p.Names())
+	}
+}
+
+func TestPipelineSkipsDisabled(t *testing.T) {
+	var log []string
+	p := systems.NewPipeline(recorder{"a", &log}, recorder{"b", &log})
+	if err := p.SetEnabled("a", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.Update(&systems.World{})
+	if want := []string{"b"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Expected %v, got %v", want, log)
+	}
+	if p.Enabled("a") || !p.Enabled("b") {
+		t.Error("Expected only b to be enabled")
+	}
+
+	if err := p.SetEnabled("missing", true); err != systems.ErrUnknownSystem {
+		t.Errorf("Expected ErrUnknownSystem, got %v", err)
+	}
+	if err := p.InsertBefore("missing", recorder{"x", &log}); err != systems.ErrUnknownSystem {
+		t.Errorf("Expected ErrUnknownSystem, got %v", err)
+	}
+}
+
+func TestCleanupRemovesLeakedEnemies(t *testing.T) {
+	path := []entities.BaseEntity{{X: 0, Y: 0}, {X: 10, Y: 0}}
+	done := entities.NewEnemy(10, 1, 3, 1, path)
+	done.PathIndex = 1
+	walking := entities.NewEnemy(10, 1, 3, 1, path)
+
+	var leaked []*entities.Enemy
+	w := &systems.World{
+		Enemies:   []*entities.Enemy{done, walking},
+		EnemyPool: utils.NewPool[entities.Enemy](),
+		OnLeak:    func(e *entities.Enemy) { leaked = append(leaked, e) },
+	}
+	systems.Cleanup{}.Update(w)
+
+	if len(w.Enemies) != 1 || w.Enemies[0] != walking {
+		t.Errorf("Expected only the walking enemy to remain, got %d", len(w.Enemies))
+	}
+	if len(leaked) != 1 || leaked[0] != done {
+		t.Errorf("Expected one leak reported, got %d", len(leaked))
+	}
+}
+
+func TestMovementMovesEnemies(t *testing.T) {
+	path := []entities.BaseEntity{{X: 0, Y: 0}, {X: 10, Y: 0}}
+	enemy := entities.NewEnemy(10, 1, 1, 2, path)
+	w := &systems.World{Enemies: []*entities.Enemy{enemy}}
+
+	systems.Movement{}.Update(w)
+	if enemy.X != 2 {
+		t.Errorf("Expected enemy at x=2, got %f", enemy.X)
+	}
+}
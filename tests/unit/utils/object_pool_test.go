@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"tower-defense/internal/utils"
+)
+
+type thing struct {
+	Name  string
+	Items []int
+}
+
+func TestPoolReusesZeroedObjects(t *testing.T) {
+	pool := utils.NewPool[thing]()
+	first := pool.Get()
+	first.Name = "used"
+	first.Items = append(first.Items, 1, 2, 3)
+
+	if !pool.Put(first) {
+		t.Fatal("Expected Put to take an object from Get")
+	}
+	if pool.InUse() != 0 || pool.Idle() != 1 {
+		t.Errorf("Expected 0 in use and 1 idle, got %d and %d", pool.InUse(), pool.Idle())
+	}
+
+	second := pool.Get()
+	if second != first {
+		t.Error("Expected the pool to hand back the recycled object")
+	}
+	if second.Name != "" || second.Items != nil {
+		t.Errorf("Expected a zeroed object, got %+v", *second)
+	}
+}
+
+func TestPoolRejectsForeignAndDoublePuts(t *testing.T) {
+	pool := utils.NewPool[thing]()
+	if pool.Put(&thing{Name: "stranger"}) {
+		t.Error("Expected Put to ignore an object the pool did not hand out")
+	}
+
+	x := pool.Get()
+	pool.Put(x)
+	if pool.Put(x) {
+		t.Error("Expected a second Put of the same object to be ignored")
+	}
+	if pool.Idle() != 1 {
+		t.Errorf("Expected 1 idle object, got %d", pool.Idle())
+	}
+	if a, b := pool.Get(), pool.Get(); a == b {
+		t.Error("Expected two Gets to return different objects")
+	}
+}
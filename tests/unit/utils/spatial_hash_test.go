@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"tower-defense/internal/utils"
+)
+
+type point struct{ x, y float64 }
+
+func TestNearMatchesLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([]point, 500)
+	hash := utils.NewSpatialHash[int](40)
+	for i := range points {
+		points[i] = point{rng.Float64()*900 - 50, rng.Float64()*700 - 50}
+		hash.Insert(i, points[i].x, points[i].y)
+	}
+	if hash.Len() != len(points) {
+		t.Fatalf("Expected %d items, got %d", len(points), hash.Len())
+	}
+
+	for q := 0; q < 200; q++ {
+		x, y, radius := rng.Float64()*800, rng.Float64()*600, rng.Float64()*150
+		var expected []int
+		for i, p := range points {
+			dx, dy := x-p.x, y-p.y
+			if dx*dx+dy*dy <= radius*radius {
+				expected = append(expected, i)
+			}
+		}
+		got := hash.Near(x, y, radius, nil)
+		sort.Ints(got)
+		if len(got) != len(expected) {
+			t.Fatalf("Query %d: expected %d items, got %d", q, len(expected), len(got))
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("Query %d: expected %v, got %v", q, expected, got)
+			}
+		}
+	}
+}
+
+func TestNearIncludesEdgeAndAppends(t *testing.T) {
+	hash := utils.NewSpatialHash[string](10)
+	hash.Insert("edge", 30, 0)
+	hash.Insert("outside", 30.5, 0)
+
+	hash.Insert("first", 1, 1)
+	hash.Insert("second", 2, 2)
+
+	got := hash.Near(0, 0, 30, []string{"kept"})
+	if len(got) != 4 || got[0] != "kept" || got[1] != "first" || got[2] != "second" || got[3] != "edge" {
+		t.Errorf("Expected [kept first second edge], got %v", got)
+	}
+}
+
+func TestClear(t *testing.T) {
+	hash := utils.NewSpatialHash[int](10)
+	hash.Insert(1, 5, 5)
+	hash.Clear()
+	if hash.Len() != 0 || len(hash.Near(5, 5, 100, nil)) != 0 {
+		t.Error("Expected an empty hash after Clear")
+	}
+	hash.Insert(2, 5, 5)
+	if got := hash.Near(5, 5, 1, nil); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected the hash to be reusable after Clear, got %v", got)
+	}
+}